@@ -0,0 +1,164 @@
+package hue
+
+import (
+	"context"
+	"time"
+)
+
+// LightAPI is the set of light operations supported by a bridge.
+type LightAPI interface {
+	SearchForNewLights(deviceIDs ...string) error
+	SearchForNewLightsWithContext(ctx context.Context, deviceIDs ...string) error
+	NewLights() ([]NewLight, ScanStatus, error)
+	NewLightsWithContext(ctx context.Context) ([]NewLight, ScanStatus, error)
+	Lights() ([]Light, error)
+	LightsWithContext(ctx context.Context) ([]Light, error)
+	Light(id string) (Light, error)
+	LightWithContext(ctx context.Context, id string) (Light, error)
+	SetLight(id string, args *LightArg) error
+	SetLightWithContext(ctx context.Context, id string, args *LightArg) error
+	SetLightState(id string, args *LightStateArg) error
+	SetLightStateWithContext(ctx context.Context, id string, args *LightStateArg) error
+	DeleteLight(id string) error
+	DeleteLightWithContext(ctx context.Context, id string) error
+	DeleteLightAndReferences(id string) error
+	DeleteLightAndReferencesWithContext(ctx context.Context, id string) error
+}
+
+// SensorAPI is the set of sensor operations supported by a bridge.
+type SensorAPI interface {
+	SearchForNewSensors(deviceIDs ...string) error
+	SearchForNewSensorsWithContext(ctx context.Context, deviceIDs ...string) error
+	NewSensors() ([]NewSensor, ScanStatus, error)
+	NewSensorsWithContext(ctx context.Context) ([]NewSensor, ScanStatus, error)
+	Sensors() ([]Sensor, error)
+	SensorsWithContext(ctx context.Context) ([]Sensor, error)
+	Sensor(id string) (Sensor, error)
+	SensorWithContext(ctx context.Context, id string) (Sensor, error)
+	SetSensor(id string, args *SensorArg) error
+	SetSensorWithContext(ctx context.Context, id string, args *SensorArg) error
+	SetSensorConfig(id string, args *SensorConfigArg) error
+	SetSensorConfigWithContext(ctx context.Context, id string, args *SensorConfigArg) error
+	SetSensorState(id string, args *SensorStateArg) error
+	SetSensorStateWithContext(ctx context.Context, id string, args *SensorStateArg) error
+	CreateSensor(sensor *Sensor) error
+	CreateSensorWithContext(ctx context.Context, sensor *Sensor) error
+	DeleteSensor(id string) error
+	DeleteSensorWithContext(ctx context.Context, id string) error
+	PairSensors(ctx context.Context) ([]Sensor, error)
+}
+
+// ConfigAPI is the set of configuration operations supported by a bridge.
+type ConfigAPI interface {
+	Config() (Config, error)
+	ConfigWithContext(ctx context.Context) (Config, error)
+	SetConfig(args *ConfigArg) error
+	SetConfigWithContext(ctx context.Context, args *ConfigArg) error
+	DeleteUser(key string) error
+	DeleteUserWithContext(ctx context.Context, key string) error
+	RevokeStaleUsers(maxAge time.Duration) ([]string, error)
+	RevokeStaleUsersWithContext(ctx context.Context, maxAge time.Duration) ([]string, error)
+	CheckForUpdate() error
+	CheckForUpdateWithContext(ctx context.Context) error
+	StartUpdate() error
+	StartUpdateWithContext(ctx context.Context) error
+	FinishUpdate() error
+	FinishUpdateWithContext(ctx context.Context) error
+	FullState() (Datastore, error)
+	FullStateWithContext(ctx context.Context) (Datastore, error)
+	Pair(appName string, identifier string) error
+	PairWithContext(ctx context.Context, appName string, identifier string, opts *PairOptions) error
+}
+
+// GroupAPI is the set of group operations supported by a bridge.
+type GroupAPI interface {
+	Groups() ([]Group, error)
+	GroupsWithContext(ctx context.Context) ([]Group, error)
+	Group(id string) (Group, error)
+	GroupWithContext(ctx context.Context, id string) (Group, error)
+	CreateGroup(group *Group) error
+	CreateGroupWithContext(ctx context.Context, group *Group) error
+	SetGroup(id string, args *GroupArg) error
+	SetGroupWithContext(ctx context.Context, id string, args *GroupArg) error
+	SetGroupAction(id string, args *GroupActionArg) error
+	SetGroupActionWithContext(ctx context.Context, id string, args *GroupActionArg) error
+	DeleteGroup(id string) error
+	DeleteGroupWithContext(ctx context.Context, id string) error
+}
+
+// SceneAPI is the set of scene operations supported by a bridge.
+type SceneAPI interface {
+	Scenes() ([]Scene, error)
+	ScenesWithContext(ctx context.Context) ([]Scene, error)
+	Scene(id string) (Scene, error)
+	SceneWithContext(ctx context.Context, id string) (Scene, error)
+	CreateScene(scene *Scene, lightStates map[string]*LightStateArg) error
+	CreateSceneWithContext(ctx context.Context, scene *Scene, lightStates map[string]*LightStateArg) error
+	SetScene(id string, args *SceneArg) error
+	SetSceneWithContext(ctx context.Context, id string, args *SceneArg) error
+	SetSceneLightState(id string, lightID string, args *LightStateArg) error
+	SetSceneLightStateWithContext(ctx context.Context, id string, lightID string, args *LightStateArg) error
+	DeleteScene(id string) error
+	DeleteSceneWithContext(ctx context.Context, id string) error
+	RecallScene(groupID string, sceneID string) error
+	RecallSceneWithContext(ctx context.Context, groupID string, sceneID string) error
+}
+
+// ScheduleAPI is the set of schedule operations supported by a bridge.
+type ScheduleAPI interface {
+	Schedules() ([]Schedule, error)
+	SchedulesWithContext(ctx context.Context) ([]Schedule, error)
+	Schedule(id string) (Schedule, error)
+	ScheduleWithContext(ctx context.Context, id string) (Schedule, error)
+	CreateSchedule(schedule *Schedule) error
+	CreateScheduleWithContext(ctx context.Context, schedule *Schedule) error
+	SetSchedule(id string, args *ScheduleArg) error
+	SetScheduleWithContext(ctx context.Context, id string, args *ScheduleArg) error
+	DeleteSchedule(id string) error
+	DeleteScheduleWithContext(ctx context.Context, id string) error
+}
+
+// RuleAPI is the set of rule operations supported by a bridge.
+type RuleAPI interface {
+	Rules() ([]Rule, error)
+	RulesWithContext(ctx context.Context) ([]Rule, error)
+	Rule(id string) (Rule, error)
+	RuleWithContext(ctx context.Context, id string) (Rule, error)
+	CreateRule(rule *Rule) error
+	CreateRuleWithContext(ctx context.Context, rule *Rule) error
+	SetRule(id string, args *RuleArg) error
+	SetRuleWithContext(ctx context.Context, id string, args *RuleArg) error
+	DeleteRule(id string) error
+	DeleteRuleWithContext(ctx context.Context, id string) error
+}
+
+// ResourceLinkAPI is the set of resource link operations supported by a bridge.
+type ResourceLinkAPI interface {
+	ResourceLinks() ([]ResourceLink, error)
+	ResourceLinksWithContext(ctx context.Context) ([]ResourceLink, error)
+	ResourceLink(id string) (ResourceLink, error)
+	ResourceLinkWithContext(ctx context.Context, id string) (ResourceLink, error)
+	CreateResourceLink(link *ResourceLink) error
+	CreateResourceLinkWithContext(ctx context.Context, link *ResourceLink) error
+	SetResourceLink(id string, args *ResourceLinkArg) error
+	SetResourceLinkWithContext(ctx context.Context, id string, args *ResourceLinkArg) error
+	DeleteResourceLink(id string) error
+	DeleteResourceLinkWithContext(ctx context.Context, id string) error
+	Resolve(ref ResourceReference) (interface{}, error)
+	ResolveWithContext(ctx context.Context, ref ResourceReference) (interface{}, error)
+}
+
+// API is the set of bridge operations which consumers can depend on in place of a *Bridge, so they can be tested
+// using an implementation such as the one in the huemock package.
+type API interface {
+	LightAPI
+	SensorAPI
+	ConfigAPI
+	GroupAPI
+	SceneAPI
+	ScheduleAPI
+	RuleAPI
+	ResourceLinkAPI
+}
+
+var _ API = (*Bridge)(nil)
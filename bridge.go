@@ -1,11 +1,18 @@
 package hue
 
 import (
+	"context"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
 )
 
 var (
@@ -50,67 +57,193 @@ type BridgeDescription struct {
 
 // Bridge represents an instance of a Hue bridge.
 // The locator discovery process will also create one instance for each profile detected that is not already running.
+// A bridge is safe for concurrent use by multiple goroutines. Username and CertificateFingerprint should be set before
+// the bridge is used, and not assigned directly afterwards; pairing and TLS verification update them safely.
+// Copies of a bridge, such as those sent by the Locator, share their lock and whether an update is in progress, but each
+// has its own Username, ClientKey, CertificateFingerprint, ID and URLs, so pairing or initializing one copy doesn't change
+// the others. The zero value is ready to use, although copies of it made before it is first used don't share anything;
+// NewBridge should be used if the bridge will be copied.
 type Bridge struct {
 	id string
 
 	// An empty username implies we have not yet paired with the bridge.
 	Username string
 
+	// The client key is only set if requested when pairing, and is used for entertainment streaming.
+	ClientKey string
+
+	// The certificate fingerprint is used to verify the bridge when using TLS.
+	// It is set the first time a certificate matching the bridge ID is seen, and should be saved along with the username.
+	CertificateFingerprint string
+
 	baseURL *url.URL
 
 	validateURL *url.URL
 
 	iconURL *url.URL
 
-	updateInProgress bool
+	client *http.Client
+
+	useTLS bool
+	// tlsErr is set if TLS couldn't be configured for the transport supplied; it is reported by InitURL.
+	tlsErr error
+
+	validateArgs bool
+
+	lightQueue *commandQueue
+	groupQueue *commandQueue
+
+	retryPolicy *RetryPolicy
+
+	// state is shared by copies of the bridge, so they can be used alongside each other.
+	// It is only accessed through shared, as it is created on first use if the bridge is a zero value.
+	state *bridgeState
+}
+
+// bridgeState synchronizes access to the parts of a bridge which change after it has been created.
+// The lock guards Username, ClientKey, CertificateFingerprint, the ID and the URLs, which are only written while initializing or pairing.
+type bridgeState struct {
+	mu               sync.RWMutex
+	updateInProgress atomic.Bool
+}
+
+// shared returns the state of the bridge, creating it if the bridge is a zero value.
+// The state of a bridge created by NewBridge is already set, so this is a single atomic load.
+func (b *Bridge) shared() *bridgeState {
+	p := (*unsafe.Pointer)(unsafe.Pointer(&b.state))
+	if state := atomic.LoadPointer(p); state != nil {
+		return (*bridgeState)(state)
+	}
+
+	// Only the first state stored is used, should several goroutines use a zero value bridge at once.
+	atomic.CompareAndSwapPointer(p, nil, unsafe.Pointer(&bridgeState{}))
+	return (*bridgeState)(atomic.LoadPointer(p))
+}
+
+// BridgeOption configures optional behaviour of a bridge instance.
+type BridgeOption func(*Bridge)
+
+// WithHTTPClient makes all requests to the bridge using the supplied client.
+func WithHTTPClient(client *http.Client) BridgeOption {
+	return func(b *Bridge) {
+		b.client = client
+	}
+}
+
+// WithTransport makes all requests to the bridge using the supplied transport, such as one configured with a proxy or custom TLS settings.
+func WithTransport(transport http.RoundTripper) BridgeOption {
+	return func(b *Bridge) {
+		b.client = &http.Client{Transport: transport}
+	}
 }
 
 // NewBridge creates a new instance of a Hue bridge.
-func NewBridge(username string) *Bridge {
-	return &Bridge{
+// Unless a client is supplied using an option, every request made by this instance shares a single pooled client.
+func NewBridge(username string, opts ...BridgeOption) *Bridge {
+	b := &Bridge{
 		Username: username,
+		client:   &http.Client{Transport: http.DefaultTransport},
+		state:    &bridgeState{},
 	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	if b.useTLS {
+		b.enableTLS()
+	}
+	if b.retryPolicy != nil {
+		// This must wrap the transport after TLS has been configured, as enableTLS only configures the transports it recognizes.
+		b.enableRetry()
+	}
+
+	return b
 }
 
 // InitURL initializes this bridge instance with the specified discovery URL.
 func (b *Bridge) InitURL(validateURL *url.URL) error {
+	return b.InitURLWithContext(context.Background(), validateURL)
+}
+
+// InitURLWithContext is like InitURL, but the request is cancelled once the supplied context is done.
+func (b *Bridge) InitURLWithContext(ctx context.Context, validateURL *url.URL) error {
+	if b.tlsErr != nil {
+		return b.tlsErr
+	}
+
+	b.shared().mu.Lock()
 	b.validateURL = validateURL
+	b.shared().mu.Unlock()
+
+	desc, err := b.DescriptionWithContext(ctx)
+	if err != nil {
+		return err
+	}
 
-	desc, err := b.Description()
+	baseURL, err := url.Parse(desc.URLBase)
 	if err != nil {
 		return err
 	}
 
+	if b.useTLS {
+		tlsBaseURL(baseURL)
+	}
+
+	b.shared().mu.Lock()
 	b.id = desc.Device.SerialNumber
-	b.baseURL, err = url.Parse(desc.URLBase)
+	b.baseURL = baseURL
+	b.shared().mu.Unlock()
+
+	if b.useTLS {
 
-	return err
+		// Connections opened before the ID was known weren't checked against it, so don't reuse them.
+		b.httpClient().CloseIdleConnections()
+	}
+
+	return nil
 }
 
 // InitIP initializes this bridge instance with the specified IP address.
 func (b *Bridge) InitIP(bridgeIP string) error {
+	return b.InitIPWithContext(context.Background(), bridgeIP)
+}
+
+// InitIPWithContext is like InitIP, but the request is cancelled once the supplied context is done.
+func (b *Bridge) InitIPWithContext(ctx context.Context, bridgeIP string) error {
 	bridgeURL, err := bridgeDescURLFromIP(bridgeIP)
 	if err != nil {
 		return err
 	}
 
-	return b.InitURL(bridgeURL)
+	return b.InitURLWithContext(ctx, bridgeURL)
 }
 
 // Description parses the validation XML file present on every Hue bridge.
 // See http://www.developers.meethue.com/documentation/hue-bridge-discovery for details of the response format.
 func (b *Bridge) Description() (BridgeDescription, error) {
+	return b.DescriptionWithContext(context.Background())
+}
+
+// DescriptionWithContext is like Description, but the request is cancelled once the supplied context is done.
+func (b *Bridge) DescriptionWithContext(ctx context.Context) (BridgeDescription, error) {
 	desc := BridgeDescription{}
 
-	if b.validateURL == nil {
+	b.shared().mu.RLock()
+	validateURL := b.validateURL
+	b.shared().mu.RUnlock()
+
+	if validateURL == nil {
 		return desc, ErrBridgeNotConfigured
 	}
 
-	res, err := http.Get(b.validateURL.String())
+	res, err := b.get(ctx, validateURL.String())
 	if err != nil {
 		return desc, err
 	}
 
+	defer closeBody(res.Body)
+
 	err = xml.NewDecoder(res.Body).Decode(&desc)
 	if err != nil {
 		return desc, err
@@ -126,15 +259,114 @@ func (b *Bridge) Description() (BridgeDescription, error) {
 }
 
 func (b *Bridge) isAvailable() bool {
-	return len(b.Username) > 0 && len(b.baseURL.Host) > 0
+	b.shared().mu.RLock()
+	defer b.shared().mu.RUnlock()
+
+	return len(b.Username) > 0 && b.baseURL != nil && len(b.baseURL.Host) > 0
 }
 
 // ID returns the unique ID of the bridge
 func (b *Bridge) ID() string {
+	b.shared().mu.RLock()
+	defer b.shared().mu.RUnlock()
+
 	return b.id
 }
 
 // IsUpdating returns whether a bridge is in the process of updating or not.
 func (b *Bridge) IsUpdating() bool {
-	return b.updateInProgress
+	return b.shared().updateInProgress.Load()
+}
+
+// setUpdating records whether the bridge is in the process of updating.
+func (b *Bridge) setUpdating(updating bool) {
+	b.shared().updateInProgress.Store(updating)
+}
+
+// username returns the username requests are made with.
+func (b *Bridge) username() string {
+	b.shared().mu.RLock()
+	defer b.shared().mu.RUnlock()
+
+	return b.Username
+}
+
+// apiURL returns the URL of the API for the username, which every resource path is appended to.
+func (b *Bridge) apiURL() string {
+	b.shared().mu.RLock()
+	defer b.shared().mu.RUnlock()
+
+	if b.baseURL == nil {
+		return ""
+	}
+
+	return b.baseURL.String() + "api/" + b.Username
+}
+
+// deleteResource removes the resource at the specified path, relative to the API user, from the bridge.
+// If the bridge refuses the delete, the ResponseError it reported is returned.
+func (b *Bridge) deleteResource(ctx context.Context, path string) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + path
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := b.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer closeBody(resp.Body)
+
+	var respEntries responseEntries
+	err = json.NewDecoder(resp.Body).Decode(&respEntries)
+	if err != nil {
+		return err
+	}
+
+	for _, respEntry := range respEntries {
+		var e deleteResponseEntry
+		if err = json.Unmarshal(respEntry, &e); err != nil {
+			return err
+		}
+
+		if e.Error.Type > 0 {
+			return e.Error
+		}
+	}
+
+	return nil
+}
+
+// get issues a GET request for the specified URL, which is cancelled once the supplied context is done.
+func (b *Bridge) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.httpClient().Do(req)
+}
+
+// httpClient returns the client all requests to the bridge are made with.
+func (b *Bridge) httpClient() *http.Client {
+	if b.client == nil {
+		return http.DefaultClient
+	}
+
+	return b.client
+}
+
+// closeBody reads any remaining data from a response body before closing it, so the connection can be reused.
+func closeBody(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, body)
+	body.Close()
 }
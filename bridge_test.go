@@ -0,0 +1,226 @@
+package hue
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+const testDescription = `<?xml version="1.0" encoding="UTF-8" ?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+<URLBase>%s/</URLBase>
+<device>
+<manufacturer>Royal Philips Electronics</manufacturer>
+<modelName>Philips hue bridge 2015</modelName>
+<serialNumber>001788fffe100491</serialNumber>
+</device>
+</root>`
+
+// newTestServer starts a server which serves the bridge description along with the supplied API handler.
+// The number of connections accepted by the server is tracked in conns.
+func newTestServer(api http.Handler, conns *int64) *httptest.Server {
+	srv := newUnstartedTestServer(api, conns)
+	srv.Start()
+
+	return srv
+}
+
+// newUnstartedTestServer is like newTestServer, but leaves the server to be started by the caller.
+func newUnstartedTestServer(api http.Handler, conns *int64) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/api", api)
+	mux.Handle("/api/", api)
+
+	srv := httptest.NewUnstartedServer(mux)
+	mux.HandleFunc("/description.xml", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprintf(w, testDescription, srv.URL)
+	})
+	srv.Config.ConnState = func(c net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt64(conns, 1)
+		}
+	}
+
+	return srv
+}
+
+func BenchmarkBridge_Lights(b *testing.B) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"1":{"name":"Hue lamp 1","type":"Extended color light","modelid":"LCT001","state":{"on":true,"bri":144,"colormode":"ct","ct":201}}}`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		b.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := bridge.Lights(); err != nil {
+				b.Errorf("Unable to retrieve lights: %s\n", err.Error())
+				return
+			}
+		}
+	})
+
+	b.ReportMetric(float64(atomic.LoadInt64(&conns)), "conns")
+}
+
+// countingTransport counts the requests passed through it to the default transport.
+type countingTransport struct {
+	requests int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt64(&t.requests, 1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestNewBridge_WithTransport(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	transport := &countingTransport{}
+
+	bridge := NewBridge("testuser", WithTransport(transport))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if _, err := bridge.Lights(); err != nil {
+		t.Fatalf("Unable to retrieve lights: %s\n", err.Error())
+	}
+
+	if transport.requests != 2 {
+		t.Errorf("Expected 2 requests through the supplied transport, got %d\n", transport.requests)
+	}
+}
+
+func TestBridge_ZeroValue(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	var bridge Bridge
+	bridge.Username = "testuser"
+
+	if bridge.ID() != "" || bridge.IsUpdating() {
+		t.Errorf("Expected an uninitialized bridge, got ID %s\n", bridge.ID())
+	}
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+	if _, err := bridge.Lights(); err != nil {
+		t.Fatalf("Unable to retrieve lights: %s\n", err.Error())
+	}
+}
+
+func TestBridge_Concurrent(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			w.Write([]byte(`[{"success":{"username":"paireduser"}}]`))
+		case r.Method == http.MethodPut:
+			w.Write([]byte(`[{"success":{"/lights/1/state/on":true}}]`))
+		default:
+			w.Write([]byte(`{"1":{"name":"Hue lamp 1","type":"Extended color light","modelid":"LCT001","state":{"on":true,"reachable":true}}}`))
+		}
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithRateLimit(RateLimit{LightsPerSecond: 1000, Burst: 10}))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	// The locator hands out copies of bridges, which must be safe to use alongside the original.
+	copied := *bridge
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(5)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := bridge.Lights(); err != nil && err != ErrBridgeUpdating {
+					t.Errorf("Unable to retrieve lights: %s\n", err.Error())
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				state := &LightStateArg{}
+				state.SetIsOn(true)
+				if err := copied.SetLightState("1", state); err != nil && err != ErrBridgeUpdating {
+					t.Errorf("Unable to set light state: %s\n", err.Error())
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				bridge.setUpdating(j%2 == 0)
+				bridge.IsUpdating()
+				bridge.ID()
+				bridge.QueueMetrics()
+			}
+			bridge.setUpdating(false)
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				if err := bridge.Pair("test", "concurrent"); err != nil {
+					t.Errorf("Unable to pair: %s\n", err.Error())
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				if err := copied.InitIP(srv.Listener.Addr().String()); err != nil {
+					t.Errorf("Unable to initialize bridge: %s\n", err.Error())
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if bridge.IsUpdating() {
+		t.Errorf("Expected the bridge to no longer be updating\n")
+	}
+
+	// An update started through one copy must be seen by the other.
+	copied.setUpdating(true)
+	if !bridge.IsUpdating() {
+		t.Errorf("Expected the update started through the copy to be seen by the original\n")
+	}
+	copied.setUpdating(false)
+
+	// Credentials are kept by each copy, so pairing the original doesn't change the copy.
+	if bridge.username() != "paireduser" || copied.username() != "testuser" {
+		t.Errorf("Expected usernames paireduser and testuser, got %s and %s\n", bridge.username(), copied.username())
+	}
+}
@@ -40,12 +40,14 @@ func main() {
 		fmt.Printf("Light: %+v\n", light)
 	}
 
-	newLights, err := b.NewLights()
+	newLights, lightScan, err := b.NewLights()
 	if err != nil {
 		fmt.Printf("Unable to retrieve new bridge lights: %s\n", err.Error())
 		return
 	}
 
+	fmt.Printf("Light scan: %+v\n", lightScan)
+
 	for _, newLight := range newLights {
 		fmt.Printf("New Light: %+v\n", newLight)
 	}
@@ -60,12 +62,14 @@ func main() {
 		fmt.Printf("Sensor: %+v\n", sensor)
 	}
 
-	newSensors, err := b.NewSensors()
+	newSensors, sensorScan, err := b.NewSensors()
 	if err != nil {
 		fmt.Printf("Unable to retrieve new bridge sensors: %s\n", err.Error())
 		return
 	}
 
+	fmt.Printf("Sensor scan: %+v\n", sensorScan)
+
 	for _, newSensor := range newSensors {
 		fmt.Printf("New Sensor: %+v\n", newSensor)
 	}
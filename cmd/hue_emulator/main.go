@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/rmrobinson/hue-go"
+	"github.com/rmrobinson/hue-go/emulator"
+)
+
+var (
+	listenAddr = flag.String("listenAddress", ":80", "The address to serve the emulated bridge on")
+	bridgeName = flag.String("name", "Hue emulator", "The name of the emulated bridge")
+	bridgeID   = flag.String("bridgeID", "001788fffe0e4d11", "The 16 character ID of the emulated bridge")
+	lightCount = flag.Int("lights", 2, "The number of virtual lights to create")
+)
+
+// consoleLight is a virtual light which prints each state change it receives.
+type consoleLight struct {
+	name string
+
+	mu    sync.Mutex
+	state hue.LightState
+}
+
+func (l *consoleLight) Light() hue.Light {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return hue.Light{
+		Name:             l.name,
+		Model:            "Extended color light",
+		ModelID:          "LCT015",
+		ManufacturerName: "Philips",
+		SwVersion:        "1.46.13_r26312",
+		State:            l.state,
+	}
+}
+
+func (l *consoleLight) SetState(state hue.LightState) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.state = state
+	l.state.Reachable = true
+
+	fmt.Printf("%s: %+v\n", l.name, l.state)
+	return nil
+}
+
+func main() {
+	flag.Parse()
+
+	e, err := emulator.New(*bridgeName, *bridgeID)
+	if err != nil {
+		fmt.Printf("Unable to create emulator: %s\n", err.Error())
+		return
+	}
+
+	for i := 1; i <= *lightCount; i++ {
+		light := &consoleLight{name: fmt.Sprintf("Virtual light %d", i)}
+		light.state.Reachable = true
+
+		e.AddLight(light)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	go func() {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			e.PressLinkButton()
+			fmt.Printf("Link button pressed, pairing is allowed for 30 seconds\n")
+		}
+	}()
+
+	fmt.Printf("Serving bridge %s on %s; press enter to press the link button\n", e.ID(), *listenAddr)
+
+	if err = e.ListenAndServe(ctx, *listenAddr); err != nil {
+		fmt.Printf("Unable to serve emulator: %s\n", err.Error())
+	}
+}
@@ -1,11 +1,10 @@
 package hue
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
 	"net/http"
-	"strings"
+	"time"
 )
 
 var (
@@ -13,6 +12,8 @@ var (
 	ErrBridgeNotAvailable = errors.New("bridge is not yet ready")
 	// ErrBridgeUpdating is returned if the specified bridge is currently being updated.
 	ErrBridgeUpdating = errors.New("bridge is currently being updated")
+	// ErrInvalidMaxAge is returned if stale users are to be revoked with a maximum age which isn't positive.
+	ErrInvalidMaxAge = errors.New("max age must be positive")
 )
 
 // Config represents an instance of a Hue bridge's configuration.
@@ -51,6 +52,9 @@ type Config struct {
 		} `json:"devicetypes"`
 	} `json:"swupdate"`
 
+	// Whitelist contains the applications which are authorized to use the API, keyed by their username.
+	Whitelist map[string]WhitelistEntry `json:"whitelist"`
+
 	PortalState struct {
 		SignedOn      bool   `json:"signedon"`
 		Incoming      bool   `json:"incoming"`
@@ -59,137 +63,145 @@ type Config struct {
 	} `json:"portalstate"`
 }
 
+// WhitelistEntry represents an application key which is authorized to use the API.
+type WhitelistEntry struct {
+	Key string
+
+	// Name is the application and device name supplied when pairing, separated by a '#'.
+	Name        string `json:"name"`
+	CreateDate  string `json:"create date"`
+	LastUseDate string `json:"last use date"`
+}
+
 // Config returns the configuration of the bridge
 func (b *Bridge) Config() (Config, error) {
+	return b.ConfigWithContext(context.Background())
+}
+
+// ConfigWithContext is like Config, but the request is cancelled once the supplied context is done.
+func (b *Bridge) ConfigWithContext(ctx context.Context) (Config, error) {
 	config := Config{}
 	if !b.isAvailable() {
 		return config, ErrBridgeNotAvailable
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/config"
+	url := b.apiURL() + "/config"
 
-	res, err := http.Get(url)
+	res, err := b.get(ctx, url)
 	if err != nil {
 		return config, err
 	}
 
-	err = json.NewDecoder(res.Body).Decode(&config)
-	return config, err
+	defer closeBody(res.Body)
+
+	err = decodeResponse(res.Body, &config)
+	if err != nil {
+		return config, err
+	}
+
+	config.deriveWhitelistKeys()
+
+	return config, nil
+}
+
+// deriveWhitelistKeys sets the Key of each whitelist entry to the username it is listed under.
+func (c *Config) deriveWhitelistKeys() {
+	for key, entry := range c.Whitelist {
+		entry.Key = key
+		c.Whitelist[key] = entry
+	}
 }
 
 // SetConfig applies the specified config options to the bridge.
 func (b *Bridge) SetConfig(args *ConfigArg) error {
+	return b.SetConfigWithContext(context.Background(), args)
+}
+
+// SetConfigWithContext is like SetConfig, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetConfigWithContext(ctx context.Context, args *ConfigArg) error {
 	if !b.isAvailable() {
 		return ErrBridgeNotAvailable
-	} else if b.updateInProgress {
+	} else if b.IsUpdating() {
 		return ErrBridgeUpdating
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/config"
-	buf := new(bytes.Buffer)
-
-	err := json.NewEncoder(buf).Encode(args.args)
-	if err != nil {
+	if err := b.validate(args); err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest(http.MethodPut, url, buf)
-	if err != nil {
-		return err
-	}
+	return b.update(ctx, "/config", (*arg)(args))
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
+// DeleteUser removes the specified application key from the whitelist of the bridge.
+func (b *Bridge) DeleteUser(key string) error {
+	return b.DeleteUserWithContext(context.Background(), key)
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+// DeleteUserWithContext is like DeleteUser, but the request is cancelled once the supplied context is done.
+func (b *Bridge) DeleteUserWithContext(ctx context.Context, key string) error {
+	return b.deleteResource(ctx, "/config/whitelist/"+key)
+}
+
+// RevokeStaleUsers removes every application key which has not been used for longer than the specified duration.
+// The key currently in use by this bridge instance is never removed. The keys which were removed are returned.
+// ErrInvalidMaxAge is returned, without removing any keys, if the duration isn't positive.
+func (b *Bridge) RevokeStaleUsers(maxAge time.Duration) ([]string, error) {
+	return b.RevokeStaleUsersWithContext(context.Background(), maxAge)
+}
+
+// RevokeStaleUsersWithContext is like RevokeStaleUsers, but the request is cancelled once the supplied context is done.
+func (b *Bridge) RevokeStaleUsersWithContext(ctx context.Context, maxAge time.Duration) ([]string, error) {
+	if maxAge <= 0 {
+		return nil, ErrInvalidMaxAge
 	}
 
-	var respEntries responseEntries
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
+	config, err := b.ConfigWithContext(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	for _, respEntry := range respEntries {
-		var e responseEntry
-		if err = json.Unmarshal(respEntry, &e); err != nil {
-			return err
-		}
-
-		if e.Error.Type > 0 {
-			if args.errors == nil {
-				args.errors = make(map[string]ResponseError)
-			}
+	cutoff := time.Now().UTC().Add(-maxAge)
 
-			keys := strings.Split(e.Error.Address, "/")
-			key := keys[len(keys)-1]
-
-			args.errors[key] = e.Error
-		} else {
-			for path, jsonValue := range e.Success {
-				keys := strings.Split(path, "/")
-
-				key := keys[len(keys)-1]
-
-				if key == "name" {
-					var v string
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
+	var revoked []string
+	for key, entry := range config.Whitelist {
+		if key == b.username() {
+			continue
+		}
 
-					args.args[key] = v
-				}
+		lastUse, err := time.Parse(timestampLayout, entry.LastUseDate)
+		if err != nil {
+			// Keys which have never been used report no last use date, so fall back to when they were created.
+			lastUse, err = time.Parse(timestampLayout, entry.CreateDate)
+			if err != nil {
+				continue
 			}
 		}
-	}
 
-	return nil
-}
-
-// Pair sets up the bridge with a new user.
-func (b *Bridge) Pair(appName string, identifier string) error {
-	url := b.baseURL.String() + "api"
-
-	type reqBody struct {
-		DeviceType string `json:"devicetype"`
-	}
-
-	jsonReq := &reqBody{DeviceType: appName + "#" + identifier}
-
-	buf := new(bytes.Buffer)
-	json.NewEncoder(buf).Encode(jsonReq)
-
-	res, err := http.Post(url, "application/json; charset=utf-8", buf)
-	if err != nil {
-		return err
-	}
+		if lastUse.After(cutoff) {
+			continue
+		}
 
-	var respBody struct {
-		successData struct {
-			username string
-		} `json:"success,omitempty"`
-	}
+		if err = b.DeleteUserWithContext(ctx, key); err != nil {
+			return revoked, err
+		}
 
-	err = json.NewDecoder(res.Body).Decode(&respBody)
-	if err != nil {
-		return err
+		revoked = append(revoked, key)
 	}
 
-	b.Username = respBody.successData.username
-
-	return nil
+	return revoked, nil
 }
 
 // CheckForUpdate returns whether there is a software update available for the Hue bridge.
 func (b *Bridge) CheckForUpdate() error {
+	return b.CheckForUpdateWithContext(context.Background())
+}
+
+// CheckForUpdateWithContext is like CheckForUpdate, but the request is cancelled once the supplied context is done.
+func (b *Bridge) CheckForUpdateWithContext(ctx context.Context) error {
 	if !b.isAvailable() {
 		return ErrBridgeNotAvailable
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/config"
-
 	var reqBody struct {
 		SwUpdate struct {
 			CheckForUpdate bool `json:"checkforupdate"`
@@ -198,55 +210,20 @@ func (b *Bridge) CheckForUpdate() error {
 
 	reqBody.SwUpdate.CheckForUpdate = true
 
-	buf := new(bytes.Buffer)
-
-	err := json.NewEncoder(buf).Encode(reqBody)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPut, url, buf)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	var respEntries responseEntries
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
-	if err != nil {
-		return err
-	}
-
-	for _, respEntry := range respEntries {
-		var e responseEntry
-		if err = json.Unmarshal(respEntry, &e); err != nil {
-			return err
-		}
-
-		if e.Error.Type > 0 {
-			err = errors.New(e.Error.Description)
-			return err
-		}
-	}
-
-	return nil
+	return b.do(ctx, http.MethodPut, "/config", reqBody)
 }
 
 // StartUpdate kicks off the update process for the Hue bridge.
 func (b *Bridge) StartUpdate() error {
+	return b.StartUpdateWithContext(context.Background())
+}
+
+// StartUpdateWithContext is like StartUpdate, but the request is cancelled once the supplied context is done.
+func (b *Bridge) StartUpdateWithContext(ctx context.Context) error {
 	if !b.isAvailable() {
 		return ErrBridgeNotAvailable
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/config"
-
 	var reqBody struct {
 		SwUpdate struct {
 			UpdateState int `json:"updatestate"`
@@ -255,54 +232,20 @@ func (b *Bridge) StartUpdate() error {
 
 	reqBody.SwUpdate.UpdateState = 3
 
-	buf := new(bytes.Buffer)
-	err := json.NewEncoder(buf).Encode(reqBody)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPut, url, buf)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	var respEntries responseEntries
-
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
-	if err != nil {
-		return err
-	}
-
-	for _, respEntry := range respEntries {
-		var e responseEntry
-		if err = json.Unmarshal(respEntry, &e); err != nil {
-			return err
-		}
-
-		if e.Error.Type > 0 {
-			return errors.New(e.Error.Description)
-		}
-	}
-
-	return nil
+	return b.do(ctx, http.MethodPut, "/config", reqBody)
 }
 
 // FinishUpdate completes the update process
 func (b *Bridge) FinishUpdate() error {
+	return b.FinishUpdateWithContext(context.Background())
+}
+
+// FinishUpdateWithContext is like FinishUpdate, but the request is cancelled once the supplied context is done.
+func (b *Bridge) FinishUpdateWithContext(ctx context.Context) error {
 	if !b.isAvailable() {
 		return ErrBridgeNotAvailable
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/config"
-
 	var reqBody struct {
 		SwUpdate struct {
 			Notify bool `json:"notify"`
@@ -311,42 +254,5 @@ func (b *Bridge) FinishUpdate() error {
 
 	reqBody.SwUpdate.Notify = false
 
-	buf := new(bytes.Buffer)
-	err := json.NewEncoder(buf).Encode(reqBody)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPut, url, buf)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	var respEntries responseEntries
-
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
-	if err != nil {
-		return err
-	}
-
-	for _, respEntry := range respEntries {
-		var e responseEntry
-		if err = json.Unmarshal(respEntry, &e); err != nil {
-			return err
-		}
-
-		if e.Error.Type > 0 {
-			return errors.New(e.Error.Description)
-		}
-	}
-
-	return nil
+	return b.do(ctx, http.MethodPut, "/config", reqBody)
 }
@@ -13,6 +13,18 @@ func (c *ConfigArg) Errors() map[string]ResponseError {
 	return c.errors
 }
 
+// Validate checks the values which have been set, returning ValidationErrors for any which the bridge would reject.
+func (c *ConfigArg) Validate() error {
+	v := newValidator(c.args)
+	v.length("name", 4, 16)
+	v.length("proxyaddress", 0, 40)
+	v.ipv4("ipaddress")
+	v.ipv4("netmask")
+	v.ipv4("gateway")
+
+	return v.err()
+}
+
 // SetProxyPort saves the specified value to be applied.
 func (c *ConfigArg) SetProxyPort(port uint16) {
 	if c.args == nil {
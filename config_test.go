@@ -0,0 +1,120 @@
+package hue
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testWhitelist returns a whitelist containing keys which were last used, or if never used were created, at various times.
+func testWhitelist() string {
+	old := time.Now().UTC().Add(-48 * time.Hour).Format(timestampLayout)
+	recent := time.Now().UTC().Add(-time.Hour).Format(timestampLayout)
+
+	return `{"whitelist":{
+		"testuser":{"name":"hue-go#test","create date":"` + old + `","last use date":"` + old + `"},
+		"stale":{"name":"app#stale","create date":"` + old + `","last use date":"` + old + `"},
+		"neverused":{"name":"app#neverused","create date":"` + old + `","last use date":""},
+		"newunused":{"name":"app#newunused","create date":"` + recent + `","last use date":""},
+		"recent":{"name":"app#recent","create date":"` + old + `","last use date":"` + recent + `"},
+		"nodates":{"name":"app#nodates","create date":"","last use date":""}
+	}}`
+}
+
+func TestBridge_RevokeStaleUsers(t *testing.T) {
+	var mu sync.Mutex
+	var deleted []string
+
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			mu.Lock()
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/api/testuser/config/whitelist/"))
+			mu.Unlock()
+
+			w.Write([]byte(`[{"success":"` + r.URL.Path + ` deleted"}]`))
+			return
+		}
+
+		w.Write([]byte(testWhitelist()))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	revoked, err := bridge.RevokeStaleUsers(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Unable to revoke stale users: %s\n", err.Error())
+	}
+
+	sort.Strings(revoked)
+	if len(revoked) != 2 || revoked[0] != "neverused" || revoked[1] != "stale" {
+		t.Errorf("Expected the stale and never used keys to be revoked, got %v\n", revoked)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(deleted) != 2 {
+		t.Errorf("Expected 2 keys to be deleted, got %v\n", deleted)
+	}
+}
+
+func TestBridge_RevokeStaleUsersInvalidMaxAge(t *testing.T) {
+	var conns, requests int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		w.Write([]byte(testWhitelist()))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	for _, maxAge := range []time.Duration{0, -time.Hour} {
+		if revoked, err := bridge.RevokeStaleUsers(maxAge); err != ErrInvalidMaxAge || len(revoked) != 0 {
+			t.Errorf("Expected a max age of %s to be rejected, got %v and %v\n", maxAge, revoked, err)
+		}
+	}
+	if n := atomic.LoadInt64(&requests); n != 0 {
+		t.Errorf("Expected no requests to be made, got %d\n", n)
+	}
+}
+
+func TestBridge_FullStateWhitelist(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"config":` + testWhitelist() + `}`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	ds, err := bridge.FullState()
+	if err != nil {
+		t.Fatalf("Unable to retrieve full state: %s\n", err.Error())
+	}
+
+	for key, entry := range ds.Config.Whitelist {
+		if entry.Key != key {
+			t.Errorf("Expected the whitelist entry %s to have its key set, got %s\n", key, entry.Key)
+		}
+	}
+}
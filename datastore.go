@@ -0,0 +1,101 @@
+package hue
+
+import (
+	"context"
+)
+
+// Datastore represents the complete state of a bridge, retrieved in a single request.
+type Datastore struct {
+	Config        Config
+	Lights        []Light
+	Groups        []Group
+	Scenes        []Scene
+	Schedules     []Schedule
+	Sensors       []Sensor
+	Rules         []Rule
+	ResourceLinks []ResourceLink
+}
+
+// FullState returns the complete state of the bridge.
+// This is considerably cheaper for the bridge than retrieving each resource collection separately.
+func (b *Bridge) FullState() (Datastore, error) {
+	return b.FullStateWithContext(context.Background())
+}
+
+// FullStateWithContext is like FullState, but the request is cancelled once the supplied context is done.
+func (b *Bridge) FullStateWithContext(ctx context.Context) (Datastore, error) {
+	var ds Datastore
+
+	if !b.isAvailable() {
+		return ds, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ds, ErrBridgeUpdating
+	}
+
+	url := b.apiURL()
+
+	res, err := b.get(ctx, url)
+	if err != nil {
+		return ds, err
+	}
+
+	defer closeBody(res.Body)
+
+	var respBody struct {
+		Config        Config                  `json:"config"`
+		Lights        map[string]Light        `json:"lights"`
+		Groups        map[string]Group        `json:"groups"`
+		Scenes        map[string]Scene        `json:"scenes"`
+		Schedules     map[string]Schedule     `json:"schedules"`
+		Sensors       map[string]Sensor       `json:"sensors"`
+		Rules         map[string]Rule         `json:"rules"`
+		ResourceLinks map[string]ResourceLink `json:"resourcelinks"`
+	}
+
+	err = decodeResponse(res.Body, &respBody)
+	if err != nil {
+		return ds, err
+	}
+
+	ds.Config = respBody.Config
+	ds.Config.deriveWhitelistKeys()
+
+	for id, light := range respBody.Lights {
+		light.ID = id
+		light.deriveRGB()
+
+		ds.Lights = append(ds.Lights, light)
+	}
+	for id, group := range respBody.Groups {
+		group.ID = id
+
+		ds.Groups = append(ds.Groups, group)
+	}
+	for id, scene := range respBody.Scenes {
+		scene.ID = id
+
+		ds.Scenes = append(ds.Scenes, scene)
+	}
+	for id, schedule := range respBody.Schedules {
+		schedule.ID = id
+
+		ds.Schedules = append(ds.Schedules, schedule)
+	}
+	for id, sensor := range respBody.Sensors {
+		sensor.ID = id
+
+		ds.Sensors = append(ds.Sensors, sensor)
+	}
+	for id, rule := range respBody.Rules {
+		rule.ID = id
+
+		ds.Rules = append(ds.Rules, rule)
+	}
+	for id, link := range respBody.ResourceLinks {
+		link.ID = id
+
+		ds.ResourceLinks = append(ds.ResourceLinks, link)
+	}
+
+	return ds, nil
+}
@@ -0,0 +1,402 @@
+package emulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	hue "github.com/rmrobinson/hue-go"
+	"github.com/rmrobinson/hue-go/internal/bridgeapi"
+)
+
+const (
+	swVersion  = "1935144040"
+	apiVersion = "1.35.0"
+	modelID    = "BSB002"
+)
+
+// emptyCollections are the resource collections which the emulator reports, but which are always empty.
+var emptyCollections = bridgeapi.EmptyCollections("sensors")
+
+// udn returns the UPnP device identifier of the emulator, which real bridges derive from their MAC address.
+func (e *Emulator) udn() string {
+	return "2f402f80-da50-11e1-9b23-" + e.macAddress()
+}
+
+// ServeHTTP serves the bridge description and the REST API of the emulated bridge.
+func (e *Emulator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/description.xml" {
+		bridgeapi.WriteDescription(w, "http://"+r.Host, e.Name, e.macAddress(), e.udn())
+		return
+	} else if r.URL.Path != "/api" && !strings.HasPrefix(r.URL.Path, "/api/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	parts := strings.SplitN(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api"), "/"), "/", 2)
+	username := parts[0]
+
+	path := ""
+	if len(parts) > 1 {
+		path = "/" + parts[1]
+	}
+
+	e.mu.Lock()
+	_, authorized := e.users[username]
+	e.mu.Unlock()
+
+	if len(username) < 1 && r.Method == http.MethodPost {
+		bridgeapi.WriteJSON(w, e.pair(body))
+		return
+	} else if username == "config" && r.Method == http.MethodGet {
+		bridgeapi.WriteJSON(w, e.publicConfig())
+		return
+	} else if !authorized {
+		if path == "/config" && r.Method == http.MethodGet {
+			bridgeapi.WriteJSON(w, e.publicConfig())
+			return
+		}
+
+		bridgeapi.WriteJSON(w, bridgeapi.ErrorEntries(1, "/"+strings.TrimPrefix(path, "/"), "unauthorized user"))
+		return
+	}
+
+	e.mu.Lock()
+	entry := e.users[username]
+	entry.LastUseDate = time.Now().UTC().Format("2006-01-02T15:04:05")
+	e.users[username] = entry
+	e.mu.Unlock()
+
+	bridgeapi.WriteJSON(w, e.route(r, path, body))
+}
+
+// route handles a single request from an authorized application.
+func (e *Emulator) route(r *http.Request, path string, body []byte) interface{} {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case path == "" && r.Method == http.MethodGet:
+		state := map[string]interface{}{
+			"config": e.config(r),
+			"lights": e.lightsState(),
+		}
+		for kind := range emptyCollections {
+			state[kind] = map[string]interface{}{}
+		}
+
+		return state
+	case segments[0] == "config" && len(segments) == 1 && r.Method == http.MethodGet:
+		return e.config(r)
+	case segments[0] == "lights":
+		return e.routeLights(r.Method, path, segments[1:], body)
+	case segments[0] == "groups" && len(segments) == 3 && segments[1] == "0" && segments[2] == "action" && r.Method == http.MethodPut:
+		// Group 0 always contains every light.
+		return e.setAllLightsState(body, path)
+	case emptyCollections[segments[0]] && len(segments) == 1 && r.Method == http.MethodGet:
+		return map[string]interface{}{}
+	case segments[0] == "config" || segments[0] == "lights" || emptyCollections[segments[0]]:
+		return bridgeapi.ErrorEntries(4, path, "method, "+r.Method+", not available for resource, "+path)
+	}
+
+	return bridgeapi.ErrorEntries(3, path, "resource, "+path+", not available")
+}
+
+func (e *Emulator) routeLights(method string, path string, segments []string, body []byte) interface{} {
+	if len(segments) == 0 {
+		if method == http.MethodGet {
+			return e.lightsState()
+		} else if method == http.MethodPost {
+			return bridgeapi.SuccessEntries("/lights", "Searching for new devices")
+		}
+	} else if segments[0] == "new" && len(segments) == 1 {
+		if method == http.MethodGet {
+			return map[string]string{"lastscan": "none"}
+		}
+	} else if light, ok := e.light(segments[0]); !ok {
+		return bridgeapi.ErrorEntries(3, path, "resource, "+path+", not available")
+	} else if len(segments) == 1 {
+		if method == http.MethodGet {
+			return light
+		} else if method == http.MethodPut {
+			return e.setLightName(segments[0], body, path)
+		}
+	} else if segments[1] == "state" && len(segments) == 2 {
+		if method == http.MethodPut {
+			return e.setLightState(segments[0], body, path)
+		}
+	} else {
+		return bridgeapi.ErrorEntries(3, path, "resource, "+path+", not available")
+	}
+
+	return bridgeapi.ErrorEntries(4, path, "method, "+method+", not available for resource, "+path)
+}
+
+// pair handles a request from an application to create a new user.
+func (e *Emulator) pair(body []byte) interface{} {
+	var req struct {
+		DeviceType        string `json:"devicetype"`
+		GenerateClientKey bool   `json:"generateclientkey"`
+	}
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		return bridgeapi.ErrorEntries(2, "", "body contains invalid json")
+	} else if len(req.DeviceType) < 1 {
+		return bridgeapi.ErrorEntries(5, "/devicetype", "invalid/missing parameters in body")
+	}
+
+	e.mu.Lock()
+	pressed := time.Now().Before(e.linkButtonUntil)
+	e.mu.Unlock()
+
+	if !pressed {
+		return bridgeapi.ErrorEntries(101, "", "link button not pressed")
+	}
+
+	username, err := newUsername()
+	if err != nil {
+		return bridgeapi.ErrorEntries(901, "", err.Error())
+	}
+
+	success := map[string]string{"username": username}
+	if req.GenerateClientKey {
+		if success["clientkey"], err = newClientKey(); err != nil {
+			return bridgeapi.ErrorEntries(901, "", err.Error())
+		}
+	}
+
+	e.AddUser(username, req.DeviceType)
+
+	return []interface{}{map[string]interface{}{"success": success}}
+}
+
+// publicConfig returns the subset of the config which a real bridge reports to unauthorized applications.
+func (e *Emulator) publicConfig() interface{} {
+	return map[string]interface{}{
+		"name":             e.Name,
+		"datastoreversion": "90",
+		"swversion":        swVersion,
+		"apiversion":       apiVersion,
+		"mac":              e.macAddressColons(),
+		"bridgeid":         e.id,
+		"factorynew":       false,
+		"replacesbridgeid": nil,
+		"modelid":          modelID,
+		"starterkitid":     "",
+	}
+}
+
+// config returns the config reported to authorized applications.
+func (e *Emulator) config(r *http.Request) hue.Config {
+	var c hue.Config
+
+	c.Name = e.Name
+	c.ID = e.id
+	c.SwVersion = swVersion
+	c.APIVersion = apiVersion
+	c.ModelVersion = modelID
+	c.MACAddress = e.macAddressColons()
+	c.IsDhcpAcquired = true
+	c.Timezone = "Etc/UTC"
+	c.Whitelist = e.Users()
+
+	if host, _, err := net.SplitHostPort(r.Host); err == nil {
+		c.IPAddress = host
+	}
+
+	e.mu.Lock()
+	c.LinkButton = time.Now().Before(e.linkButtonUntil)
+	e.mu.Unlock()
+
+	return c
+}
+
+// macAddressColons returns the MAC address of the emulator in the colon separated form.
+func (e *Emulator) macAddressColons() string {
+	mac := e.macAddress()
+
+	var parts []string
+	for i := 0; i < len(mac); i += 2 {
+		parts = append(parts, mac[i:i+2])
+	}
+
+	return strings.Join(parts, ":")
+}
+
+// light returns the attributes and state of the specified light, as reported to applications.
+func (e *Emulator) light(id string) (hue.Light, bool) {
+	e.mu.Lock()
+	vl, ok := e.lights[id]
+	name, renamed := e.names[id]
+	e.mu.Unlock()
+
+	if !ok {
+		return hue.Light{}, false
+	}
+
+	light := vl.Light()
+	light.ID = id
+
+	if renamed {
+		light.Name = name
+	}
+	if len(light.UniqueID) < 1 {
+		// Derive a stable Zigbee style address from the bridge MAC address and the light ID.
+		n, _ := strconv.Atoi(id)
+		light.UniqueID = fmt.Sprintf("%s:%02x:%02x-0b", e.macAddressColons(), n>>8, n&0xff)
+	}
+
+	return light, true
+}
+
+// lightIDs returns the IDs of each of the lights, in order.
+func (e *Emulator) lightIDs() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ids := make([]string, 0, len(e.lights))
+	for id := range e.lights {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.Atoi(ids[i])
+		b, _ := strconv.Atoi(ids[j])
+		return a < b
+	})
+
+	return ids
+}
+
+func (e *Emulator) lightsState() map[string]hue.Light {
+	ret := make(map[string]hue.Light)
+
+	for _, id := range e.lightIDs() {
+		if light, ok := e.light(id); ok {
+			ret[id] = light
+		}
+	}
+
+	return ret
+}
+
+func (e *Emulator) setLightName(id string, body []byte, path string) interface{} {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(body, &values); err != nil {
+		return bridgeapi.ErrorEntries(2, path, "body contains invalid json")
+	}
+
+	var entries []interface{}
+
+	for _, key := range bridgeapi.SortedKeys(values) {
+		address := path + "/" + key
+
+		var name string
+		if key != "name" {
+			entries = append(entries, bridgeapi.ErrorEntry(6, address, "parameter, "+key+", not available"))
+		} else if err := json.Unmarshal(values[key], &name); err != nil || len(name) < 1 || len(name) > 32 {
+			entries = append(entries, bridgeapi.ErrorEntry(7, address, "invalid value, "+string(values[key])+", for parameter, "+key))
+		} else {
+			e.mu.Lock()
+			e.names[id] = name
+			e.mu.Unlock()
+
+			entries = append(entries, bridgeapi.SuccessEntry(address, name))
+		}
+	}
+
+	return entries
+}
+
+// setLightState applies the requested changes to the state of the specified light.
+func (e *Emulator) setLightState(id string, body []byte, path string) []interface{} {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(body, &values); err != nil {
+		return bridgeapi.ErrorEntries(2, path, "body contains invalid json")
+	}
+
+	e.mu.Lock()
+	vl, ok := e.lights[id]
+	e.mu.Unlock()
+
+	if !ok {
+		return bridgeapi.ErrorEntries(3, path, "resource, "+path+", not available")
+	}
+
+	state := vl.Light().State
+	bridgeapi.ApplyIncrements(state, values)
+
+	var entries []interface{}
+	filtered := make(map[string]json.RawMessage)
+
+	for _, key := range bridgeapi.SortedKeys(values) {
+		if key == "transitiontime" {
+			entries = append(entries, bridgeapi.SuccessEntry(path+"/"+key, values[key]))
+		} else {
+			filtered[key] = values[key]
+		}
+	}
+
+	applied, changed := bridgeapi.ApplyValues(&state, filtered, path, bridgeapi.LightStateKeys)
+	entries = append(entries, applied...)
+
+	if len(changed) < 1 {
+		return entries
+	}
+
+	for _, key := range changed {
+		switch key {
+		case "xy":
+			state.ColorMode = "xy"
+		case "ct":
+			state.ColorMode = "ct"
+		case "hue", "sat":
+			state.ColorMode = "hs"
+		}
+	}
+
+	if err := vl.SetState(state); err != nil {
+		// The light didn't accept the change, so report each of the values as having failed.
+		for i, entry := range entries {
+			if success, ok := entry.(map[string]interface{})["success"]; ok {
+				for address := range success.(map[string]interface{}) {
+					entries[i] = bridgeapi.ErrorEntry(901, address, err.Error())
+				}
+			}
+		}
+	}
+
+	return entries
+}
+
+// setAllLightsState applies the requested changes to every light.
+func (e *Emulator) setAllLightsState(body []byte, path string) interface{} {
+	var entries []interface{}
+
+	for _, id := range e.lightIDs() {
+		for _, entry := range e.setLightState(id, body, "/lights/"+id+"/state") {
+			if _, failed := entry.(map[string]interface{})["error"]; failed {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	var values map[string]json.RawMessage
+	json.Unmarshal(body, &values)
+
+	for _, key := range bridgeapi.SortedKeys(values) {
+		entries = append(entries, bridgeapi.SuccessEntry(path+"/"+key, values[key]))
+	}
+
+	return entries
+}
@@ -0,0 +1,189 @@
+// Package emulator impersonates a Hue bridge, allowing Hue compatible applications to control lights which aren't Hue lights.
+//
+// Lights are supplied by implementing the VirtualLight interface; the emulator serves the bridge description, answers
+// SSDP searches and implements the lights portion of the v1 REST API, routing any state changes to the matching light.
+package emulator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	hue "github.com/rmrobinson/hue-go"
+)
+
+// ErrInvalidBridgeID is returned if the supplied bridge ID is not 16 hexadecimal characters.
+var ErrInvalidBridgeID = errors.New("bridge ID must be 16 hexadecimal characters")
+
+// linkButtonDuration is how long pairing is allowed after the virtual link button is pressed, matching a real bridge.
+const linkButtonDuration = 30 * time.Second
+
+// VirtualLight is a light which can be controlled through the emulated bridge.
+type VirtualLight interface {
+	// Light returns the attributes and current state of the light. The ID is assigned by the emulator and is ignored.
+	Light() hue.Light
+
+	// SetState applies the supplied state to the light.
+	// The state is the current state of the light with the requested changes applied.
+	SetState(state hue.LightState) error
+}
+
+// Emulator is an emulated Hue bridge.
+type Emulator struct {
+	// Name is reported as the name of the bridge.
+	Name string
+
+	id string
+
+	mu sync.Mutex
+
+	lights      map[string]VirtualLight
+	names       map[string]string
+	nextLightID int
+
+	users           map[string]hue.WhitelistEntry
+	linkButtonUntil time.Time
+}
+
+// New creates an emulated bridge with the specified name and bridge ID.
+// The bridge ID is 16 hexadecimal characters, and should be unique on the network.
+func New(name string, id string) (*Emulator, error) {
+	if _, err := hex.DecodeString(id); err != nil || len(id) != 16 {
+		return nil, ErrInvalidBridgeID
+	}
+
+	e := &Emulator{
+		Name:   name,
+		id:     strings.ToUpper(id),
+		lights: make(map[string]VirtualLight),
+		names:  make(map[string]string),
+		users:  make(map[string]hue.WhitelistEntry),
+	}
+
+	return e, nil
+}
+
+// ID returns the bridge ID of the emulator.
+func (e *Emulator) ID() string {
+	return e.id
+}
+
+// macAddress returns the MAC address of the emulator, which the bridge ID is derived from.
+func (e *Emulator) macAddress() string {
+	return strings.ToLower(e.id[:6] + e.id[10:])
+}
+
+// AddLight makes the supplied light available through the emulator, returning the ID it was assigned.
+func (e *Emulator) AddLight(light VirtualLight) string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.nextLightID++
+	id := strconv.Itoa(e.nextLightID)
+	e.lights[id] = light
+
+	return id
+}
+
+// RemoveLight stops the specified light from being available through the emulator.
+func (e *Emulator) RemoveLight(id string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.lights, id)
+	delete(e.names, id)
+}
+
+// PressLinkButton presses the virtual link button, allowing applications to pair with the emulator for the next 30 seconds.
+func (e *Emulator) PressLinkButton() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.linkButtonUntil = time.Now().Add(linkButtonDuration)
+}
+
+// AddUser authorizes the specified username without pairing, such as to restore the users from a previous run.
+func (e *Emulator) AddUser(username string, name string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.users[username] = hue.WhitelistEntry{
+		Key:        username,
+		Name:       name,
+		CreateDate: time.Now().UTC().Format("2006-01-02T15:04:05"),
+	}
+}
+
+// Users returns the applications which have paired with the emulator, keyed by their username.
+func (e *Emulator) Users() map[string]hue.WhitelistEntry {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	ret := make(map[string]hue.WhitelistEntry, len(e.users))
+	for username, entry := range e.users {
+		ret[username] = entry
+	}
+
+	return ret
+}
+
+// ListenAndServe serves the emulated bridge on the specified address and answers SSDP searches until the context is done.
+// Hue applications expect the bridge to be available on port 80.
+func (e *Emulator) ListenAndServe(ctx context.Context, addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{Handler: e}
+	errs := make(chan error, 2)
+
+	go func() {
+		errs <- srv.Serve(l)
+	}()
+	go func() {
+		errs <- e.ServeSSDP(ctx, l.Addr().(*net.TCPAddr).Port)
+	}()
+
+	select {
+	case <-ctx.Done():
+	case err = <-errs:
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv.Shutdown(shutdownCtx)
+
+	if err == http.ErrServerClosed {
+		return nil
+	}
+
+	return err
+}
+
+// newUsername generates a random username for a newly paired application.
+func newUsername() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// newClientKey generates a random client key for a newly paired application.
+func newClientKey() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return strings.ToUpper(hex.EncodeToString(buf)), nil
+}
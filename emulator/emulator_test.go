@@ -0,0 +1,182 @@
+package emulator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	hue "github.com/rmrobinson/hue-go"
+)
+
+// testLight is a virtual light which records the state applied to it.
+type testLight struct {
+	mu    sync.Mutex
+	state hue.LightState
+}
+
+func (l *testLight) Light() hue.Light {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return hue.Light{
+		Name:    "Test light",
+		Model:   "Extended color light",
+		ModelID: "LCT015",
+		State:   l.state,
+	}
+}
+
+func (l *testLight) SetState(state hue.LightState) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.state = state
+	return nil
+}
+
+func newTestEmulator(t *testing.T) (*Emulator, *httptest.Server) {
+	e, err := New("Test emulator", "001788fffe100491")
+	if err != nil {
+		t.Fatalf("Unable to create emulator: %s\n", err.Error())
+	}
+
+	return e, httptest.NewServer(e)
+}
+
+func TestEmulator_Pair(t *testing.T) {
+	e, srv := newTestEmulator(t)
+	defer srv.Close()
+
+	b := hue.NewBridge("")
+	if err := b.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if b.ID() != "001788100491" {
+		t.Errorf("Expected serial number 001788100491, got %s\n", b.ID())
+	}
+
+	if err := b.Pair("emulator", "test"); !errors.Is(err, hue.ErrLinkButtonNotPressed) {
+		t.Errorf("Expected link button not pressed, got %v\n", err)
+	}
+
+	e.PressLinkButton()
+
+	if err := b.Pair("emulator", "test"); err != nil {
+		t.Fatalf("Unable to pair: %s\n", err.Error())
+	} else if _, ok := e.Users()[b.Username]; !ok {
+		t.Errorf("Expected %s to be added to the whitelist\n", b.Username)
+	}
+
+	config, err := b.Config()
+	if err != nil {
+		t.Fatalf("Unable to retrieve config: %s\n", err.Error())
+	} else if config.ID != "001788FFFE100491" {
+		t.Errorf("Expected bridge ID 001788FFFE100491, got %s\n", config.ID)
+	}
+}
+
+func TestEmulator_SetLightState(t *testing.T) {
+	e, srv := newTestEmulator(t)
+	defer srv.Close()
+
+	e.AddUser("testuser", "emulator#test")
+	light := &testLight{}
+	id := e.AddLight(light)
+
+	b := hue.NewBridge("testuser")
+	if err := b.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	var args hue.LightStateArg
+	args.SetIsOn(true)
+	args.SetXY(hue.XY{X: 0.5, Y: 0.4})
+
+	if err := b.SetLightState(id, &args); err != nil {
+		t.Fatalf("Unable to set light state: %s\n", err.Error())
+	}
+
+	state := light.Light().State
+	if !state.On || state.XY != [2]float64{0.5, 0.4} || state.ColorMode != "xy" {
+		t.Errorf("Expected state to be applied to the light, got %+v\n", state)
+	}
+
+	lights, err := b.Lights()
+	if err != nil {
+		t.Fatalf("Unable to retrieve lights: %s\n", err.Error())
+	} else if len(lights) != 1 || lights[0].ID != id || !lights[0].State.On {
+		t.Errorf("Unexpected lights returned: %+v\n", lights)
+	}
+
+	b.Username = "unknown"
+	if _, err = b.Light(id); err == nil {
+		t.Errorf("Expected unknown user to be rejected\n")
+	}
+}
+
+func TestEmulator_SetLightStateIncrements(t *testing.T) {
+	e, srv := newTestEmulator(t)
+	defer srv.Close()
+
+	e.AddUser("testuser", "emulator#test")
+	light := &testLight{state: hue.LightState{On: true, Brightness: 100, ColorTemperature: 160}}
+	id := e.AddLight(light)
+
+	b := hue.NewBridge("testuser")
+	if err := b.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	var args hue.LightStateArg
+	args.SetBrightnessIncrement(-30)
+	args.SetColourTemperatureIncrement(-50)
+
+	if err := b.SetLightState(id, &args); err != nil {
+		t.Fatalf("Unable to set light state: %s\n", err.Error())
+	}
+
+	state := light.Light().State
+	if state.Brightness != 70 || state.ColorTemperature != 153 || state.ColorMode != "ct" {
+		t.Errorf("Expected the increments to be applied to the light, got %+v\n", state)
+	}
+	if args.Brightness() != 70 || args.ColourTemperature() != 153 {
+		t.Errorf("Expected the resulting values to be reported, got %d and %d\n", args.Brightness(), args.ColourTemperature())
+	}
+}
+
+func TestEmulator_SearchResponse(t *testing.T) {
+	e, err := New("Test emulator", "001788fffe100491")
+	if err != nil {
+		t.Fatalf("Unable to create emulator: %s\n", err.Error())
+	}
+
+	if targets := e.searchTargets("ssdp:all"); len(targets) != 3 {
+		t.Errorf("Expected 3 search targets, got %d\n", len(targets))
+	}
+	if targets := e.searchTargets("urn:schemas-upnp-org:device:MediaRenderer:1"); len(targets) != 0 {
+		t.Errorf("Expected no search targets, got %d\n", len(targets))
+	}
+
+	data := e.searchResponse("http://192.168.1.2:80/description.xml", "upnp:rootdevice")
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), nil)
+	if err != nil {
+		t.Fatalf("Unable to parse search response: %s\n", err.Error())
+	}
+
+	if !strings.Contains(resp.Header.Get("Server"), "IpBridge") {
+		t.Errorf("Expected server to identify as a bridge, got %s\n", resp.Header.Get("Server"))
+	}
+	if resp.Header.Get("Location") != "http://192.168.1.2:80/description.xml" {
+		t.Errorf("Unexpected location %s\n", resp.Header.Get("Location"))
+	}
+	if resp.Header.Get("hue-bridgeid") != "001788FFFE100491" {
+		t.Errorf("Unexpected bridge ID %s\n", resp.Header.Get("hue-bridgeid"))
+	}
+}
@@ -0,0 +1,109 @@
+package emulator
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ssdpAddr is the multicast group SSDP searches are sent to.
+var ssdpAddr = &net.UDPAddr{IP: net.IPv4(239, 255, 255, 250), Port: 1900}
+
+// ssdpServer is the server header which the locator, and Hue applications, use to recognize a bridge.
+const ssdpServer = "Linux/3.14.0 UPnP/1.0 IpBridge/1.26.0"
+
+// ServeSSDP answers SSDP M-SEARCH requests for the emulated bridge until the context is done.
+// The location of the description is reported using the supplied HTTP port, on the local address the search arrived on.
+func (e *Emulator) ServeSSDP(ctx context.Context, httpPort int) error {
+	conn, err := net.ListenMulticastUDP("udp4", nil, ssdpAddr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buf := make([]byte, 2048)
+	for {
+		n, src, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(buf[:n])))
+		if err != nil || req.Method != "M-SEARCH" {
+			continue
+		}
+
+		e.answerSearch(src, req.Header.Get("ST"), httpPort)
+	}
+}
+
+// answerSearch sends the responses to a single search, if it matches the emulated bridge.
+func (e *Emulator) answerSearch(src *net.UDPAddr, st string, httpPort int) {
+	targets := e.searchTargets(st)
+	if len(targets) < 1 {
+		return
+	}
+
+	// Reply from the address the searcher can reach us on, which is also the address advertised to it.
+	conn, err := net.DialUDP("udp4", nil, src)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	ip := conn.LocalAddr().(*net.UDPAddr).IP
+	location := fmt.Sprintf("http://%s/description.xml", net.JoinHostPort(ip.String(), fmt.Sprint(httpPort)))
+
+	for _, target := range targets {
+		conn.Write(e.searchResponse(location, target))
+	}
+}
+
+// searchTargets returns the search targets the bridge responds to for the requested search target.
+func (e *Emulator) searchTargets(st string) []string {
+	rootDevice := "upnp:rootdevice"
+	uuid := "uuid:" + e.udn()
+	basic := "urn:schemas-upnp-org:device:basic:1"
+
+	switch strings.ToLower(st) {
+	case "ssdp:all":
+		return []string{rootDevice, uuid, basic}
+	case rootDevice, basic, strings.ToLower(uuid):
+		return []string{st}
+	}
+
+	return nil
+}
+
+// searchResponse formats the response to a search for the specified target, matching the response of a real bridge.
+func (e *Emulator) searchResponse(location string, st string) []byte {
+	usn := "uuid:" + e.udn()
+	if st != usn {
+		usn += "::" + st
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("HTTP/1.1 200 OK\r\n")
+	buf.WriteString("HOST: 239.255.255.250:1900\r\n")
+	buf.WriteString("EXT:\r\n")
+	buf.WriteString("CACHE-CONTROL: max-age=100\r\n")
+	buf.WriteString("LOCATION: " + location + "\r\n")
+	buf.WriteString("SERVER: " + ssdpServer + "\r\n")
+	buf.WriteString("hue-bridgeid: " + e.id + "\r\n")
+	buf.WriteString("ST: " + st + "\r\n")
+	buf.WriteString("USN: " + usn + "\r\n")
+	buf.WriteString("\r\n")
+
+	return buf.Bytes()
+}
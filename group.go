@@ -0,0 +1,259 @@
+package hue
+
+import (
+	"context"
+)
+
+const (
+	// GroupTypeLightGroup is a multisource luminaire or arbitrary group of lights, which is deleted once its lights are removed.
+	GroupTypeLightGroup = "LightGroup"
+	// GroupTypeRoom is a group of lights that are physically located in the same place. A light can only be in one room.
+	GroupTypeRoom = "Room"
+	// GroupTypeZone is a group of lights that can be controlled together. A light can be in multiple zones.
+	GroupTypeZone = "Zone"
+	// GroupTypeLuminaire is a multisource luminaire group created automatically by the bridge.
+	GroupTypeLuminaire = "Luminaire"
+)
+
+const (
+	// GroupClassLivingRoom is the living room class of rooms.
+	GroupClassLivingRoom = "Living room"
+	// GroupClassKitchen is the kitchen class of rooms.
+	GroupClassKitchen = "Kitchen"
+	// GroupClassDining is the dining room class of rooms.
+	GroupClassDining = "Dining"
+	// GroupClassBedroom is the bedroom class of rooms.
+	GroupClassBedroom = "Bedroom"
+	// GroupClassKidsBedroom is the kids bedroom class of rooms.
+	GroupClassKidsBedroom = "Kids bedroom"
+	// GroupClassBathroom is the bathroom class of rooms.
+	GroupClassBathroom = "Bathroom"
+	// GroupClassNursery is the nursery class of rooms.
+	GroupClassNursery = "Nursery"
+	// GroupClassRecreation is the recreation class of rooms.
+	GroupClassRecreation = "Recreation"
+	// GroupClassOffice is the office class of rooms.
+	GroupClassOffice = "Office"
+	// GroupClassGym is the gym class of rooms.
+	GroupClassGym = "Gym"
+	// GroupClassHallway is the hallway class of rooms.
+	GroupClassHallway = "Hallway"
+	// GroupClassToilet is the toilet class of rooms.
+	GroupClassToilet = "Toilet"
+	// GroupClassFrontDoor is the front door class of rooms.
+	GroupClassFrontDoor = "Front door"
+	// GroupClassGarage is the garage class of rooms.
+	GroupClassGarage = "Garage"
+	// GroupClassTerrace is the terrace class of rooms.
+	GroupClassTerrace = "Terrace"
+	// GroupClassGarden is the garden class of rooms.
+	GroupClassGarden = "Garden"
+	// GroupClassDriveway is the driveway class of rooms.
+	GroupClassDriveway = "Driveway"
+	// GroupClassCarport is the carport class of rooms.
+	GroupClassCarport = "Carport"
+	// GroupClassOther is the class of rooms which don't fit any other class.
+	GroupClassOther = "Other"
+)
+
+// GroupState represents the aggregate state of the lights in a group.
+type GroupState struct {
+	AnyOn bool `json:"any_on"`
+	AllOn bool `json:"all_on"`
+}
+
+// Group represents a collection of lights which can be controlled together.
+type Group struct {
+	ID       string
+	Name     string   `json:"name"`
+	Lights   []string `json:"lights"`
+	Type     string   `json:"type"`
+	Class    string   `json:"class"`
+	ModelID  string   `json:"modelid"`
+	UniqueID string   `json:"uniqueid"`
+	Recycle  bool     `json:"recycle"`
+
+	State GroupState `json:"state"`
+
+	// Action is the last state applied to all the lights in the group.
+	Action LightState `json:"action"`
+}
+
+// Groups returns the collection of groups configured on the bridge.
+func (b *Bridge) Groups() ([]Group, error) {
+	return b.GroupsWithContext(context.Background())
+}
+
+// GroupsWithContext is like Groups, but the request is cancelled once the supplied context is done.
+func (b *Bridge) GroupsWithContext(ctx context.Context) ([]Group, error) {
+	if !b.isAvailable() {
+		return nil, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return nil, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/groups"
+
+	res, err := b.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(res.Body)
+
+	var respBody map[string]Group
+
+	err = decodeResponse(res.Body, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []Group
+	for id, group := range respBody {
+		group.ID = id
+
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
+
+// Group returns a single group from the bridge.
+// The group with ID "0" is a special group which always contains every light known to the bridge.
+func (b *Bridge) Group(id string) (Group, error) {
+	return b.GroupWithContext(context.Background(), id)
+}
+
+// GroupWithContext is like Group, but the request is cancelled once the supplied context is done.
+func (b *Bridge) GroupWithContext(ctx context.Context, id string) (Group, error) {
+	var group Group
+
+	if !b.isAvailable() {
+		return group, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return group, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/groups/" + id
+
+	resp, err := b.get(ctx, url)
+	if err != nil {
+		return group, err
+	}
+
+	defer closeBody(resp.Body)
+
+	err = decodeResponse(resp.Body, &group)
+	if err != nil {
+		return group, err
+	}
+
+	group.ID = id
+
+	return group, nil
+}
+
+// CreateGroup adds a new group to the bridge.
+// The name, lights, type and class of the supplied group are used; the ID is set once the group is created.
+func (b *Bridge) CreateGroup(group *Group) error {
+	return b.CreateGroupWithContext(context.Background(), group)
+}
+
+// CreateGroupWithContext is like CreateGroup, but the request is cancelled once the supplied context is done.
+func (b *Bridge) CreateGroupWithContext(ctx context.Context, group *Group) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	reqBody := struct {
+		Name   string   `json:"name,omitempty"`
+		Lights []string `json:"lights"`
+		Type   string   `json:"type,omitempty"`
+		Class  string   `json:"class,omitempty"`
+	}{
+		Name:   group.Name,
+		Lights: group.Lights,
+		Type:   group.Type,
+		Class:  group.Class,
+	}
+
+	if reqBody.Lights == nil {
+		reqBody.Lights = []string{}
+	}
+
+	id, err := b.create(ctx, "/groups", reqBody)
+	if err != nil {
+		return err
+	}
+
+	group.ID = id
+	return nil
+}
+
+// SetGroup updates the attributes of the specified group.
+func (b *Bridge) SetGroup(id string, args *GroupArg) error {
+	return b.SetGroupWithContext(context.Background(), id, args)
+}
+
+// SetGroupWithContext is like SetGroup, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetGroupWithContext(ctx context.Context, id string, args *GroupArg) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	if err := b.validate(args); err != nil {
+		return err
+	}
+
+	return b.update(ctx, "/groups/"+id, (*arg)(args))
+}
+
+// SetGroupAction applies the supplied state to every light in the specified group.
+func (b *Bridge) SetGroupAction(id string, args *GroupActionArg) error {
+	return b.SetGroupActionWithContext(context.Background(), id, args)
+}
+
+// SetGroupActionWithContext is like SetGroupAction, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetGroupActionWithContext(ctx context.Context, id string, args *GroupActionArg) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	if err := b.validate(args); err != nil {
+		return err
+	}
+
+	path := "/groups/" + id + "/action"
+	url := b.apiURL() + path
+
+	var respEntries responseEntries
+	var err error
+
+	if b.groupQueue != nil {
+		respEntries, err = b.groupQueue.submit(ctx, url, args.args)
+	} else {
+		respEntries, err = b.putState(ctx, url, args.args)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return (*arg)(&args.LightStateArg).applyResponse(path, respEntries)
+}
+
+// DeleteGroup removes the specified group from the bridge.
+func (b *Bridge) DeleteGroup(id string) error {
+	return b.DeleteGroupWithContext(context.Background(), id)
+}
+
+// DeleteGroupWithContext is like DeleteGroup, but the request is cancelled once the supplied context is done.
+func (b *Bridge) DeleteGroupWithContext(ctx context.Context, id string) error {
+	return b.deleteResource(ctx, "/groups/"+id)
+}
@@ -0,0 +1,98 @@
+package hue
+
+// GroupArg represents a configuration argument that can be made to a group.
+type GroupArg arg
+
+// Reset clears any set configuration options.
+func (g *GroupArg) Reset() {
+	g.args = make(map[string]interface{})
+}
+
+// Errors returns any errors encountered when applying the specified configuration.
+func (g *GroupArg) Errors() map[string]ResponseError {
+	return g.errors
+}
+
+// Validate checks the values which have been set, returning ValidationErrors for any which the bridge would reject.
+func (g *GroupArg) Validate() error {
+	v := newValidator(g.args)
+	v.length("name", 1, maxNameLength)
+
+	return v.err()
+}
+
+// SetName saves the specified value to be applied.
+func (g *GroupArg) SetName(name string) {
+	if g.args == nil {
+		g.args = make(map[string]interface{})
+	}
+
+	g.args["name"] = name
+}
+
+// Name returns the name option, if set.
+func (g *GroupArg) Name() string {
+	if ret, ok := g.args["name"].(string); ok {
+		return ret
+	}
+	return ""
+}
+
+// SetLights saves the specified value to be applied.
+// The supplied IDs replace the existing lights in the group.
+func (g *GroupArg) SetLights(lights []string) {
+	if g.args == nil {
+		g.args = make(map[string]interface{})
+	}
+
+	g.args["lights"] = lights
+}
+
+// Lights returns the lights option, if set.
+func (g *GroupArg) Lights() []string {
+	if ret, ok := g.args["lights"].([]string); ok {
+		return ret
+	}
+	return nil
+}
+
+// SetClass saves the specified value to be applied.
+// This is only valid for groups of type Room.
+func (g *GroupArg) SetClass(class string) {
+	if g.args == nil {
+		g.args = make(map[string]interface{})
+	}
+
+	g.args["class"] = class
+}
+
+// Class returns the class option, if set.
+func (g *GroupArg) Class() string {
+	if ret, ok := g.args["class"].(string); ok {
+		return ret
+	}
+	return ""
+}
+
+// GroupActionArg represents a state setting that can be applied to every light in a group.
+// All of the light state settings are available, along with the ability to recall a scene.
+type GroupActionArg struct {
+	LightStateArg
+}
+
+// SetScene saves the specified value to be applied.
+func (g *GroupActionArg) SetScene(id string) {
+	if g.args == nil {
+		g.args = make(map[string]interface{})
+	}
+
+	g.args["scene"] = id
+}
+
+// Scene returns the scene option, if set.
+func (g *GroupActionArg) Scene() string {
+	if ret, ok := g.args["scene"].(string); ok {
+		return ret
+	}
+	return ""
+}
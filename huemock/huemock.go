@@ -0,0 +1,1043 @@
+// Package huemock provides a mock implementation of the hue.API interfaces, allowing code which controls a bridge to
+// be tested without making HTTP requests.
+//
+// Each operation of the bridge has a matching function field on Bridge which, if set, supplies the result. Operations
+// without a function return zero values and no error. Every call is recorded, and can be inspected using Calls.
+package huemock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	hue "github.com/rmrobinson/hue-go"
+)
+
+var (
+	_ hue.API             = (*Bridge)(nil)
+	_ hue.LightAPI        = (*Bridge)(nil)
+	_ hue.SensorAPI       = (*Bridge)(nil)
+	_ hue.ConfigAPI       = (*Bridge)(nil)
+	_ hue.GroupAPI        = (*Bridge)(nil)
+	_ hue.SceneAPI        = (*Bridge)(nil)
+	_ hue.ScheduleAPI     = (*Bridge)(nil)
+	_ hue.RuleAPI         = (*Bridge)(nil)
+	_ hue.ResourceLinkAPI = (*Bridge)(nil)
+)
+
+// Call is a single call made to the mock.
+type Call struct {
+	// Method is the name of the operation, without any WithContext suffix.
+	Method string
+	// Args are the arguments supplied, excluding the context.
+	Args []interface{}
+}
+
+// Bridge is a mock implementation of hue.API.
+// The function fields should be set before the mock is used; it is then safe for concurrent use.
+type Bridge struct {
+	// Light operations.
+	SearchForNewLightsFunc       func(ctx context.Context, deviceIDs ...string) error
+	NewLightsFunc                func(ctx context.Context) ([]hue.NewLight, hue.ScanStatus, error)
+	LightsFunc                   func(ctx context.Context) ([]hue.Light, error)
+	LightFunc                    func(ctx context.Context, id string) (hue.Light, error)
+	SetLightFunc                 func(ctx context.Context, id string, args *hue.LightArg) error
+	SetLightStateFunc            func(ctx context.Context, id string, args *hue.LightStateArg) error
+	DeleteLightFunc              func(ctx context.Context, id string) error
+	DeleteLightAndReferencesFunc func(ctx context.Context, id string) error
+
+	// Sensor operations.
+	SearchForNewSensorsFunc func(ctx context.Context, deviceIDs ...string) error
+	NewSensorsFunc          func(ctx context.Context) ([]hue.NewSensor, hue.ScanStatus, error)
+	SensorsFunc             func(ctx context.Context) ([]hue.Sensor, error)
+	SensorFunc              func(ctx context.Context, id string) (hue.Sensor, error)
+	SetSensorFunc           func(ctx context.Context, id string, args *hue.SensorArg) error
+	SetSensorConfigFunc     func(ctx context.Context, id string, args *hue.SensorConfigArg) error
+	SetSensorStateFunc      func(ctx context.Context, id string, args *hue.SensorStateArg) error
+	CreateSensorFunc        func(ctx context.Context, sensor *hue.Sensor) error
+	DeleteSensorFunc        func(ctx context.Context, id string) error
+	PairSensorsFunc         func(ctx context.Context) ([]hue.Sensor, error)
+
+	// Config operations.
+	ConfigFunc           func(ctx context.Context) (hue.Config, error)
+	SetConfigFunc        func(ctx context.Context, args *hue.ConfigArg) error
+	DeleteUserFunc       func(ctx context.Context, key string) error
+	RevokeStaleUsersFunc func(ctx context.Context, maxAge time.Duration) ([]string, error)
+	CheckForUpdateFunc   func(ctx context.Context) error
+	StartUpdateFunc      func(ctx context.Context) error
+	FinishUpdateFunc     func(ctx context.Context) error
+	FullStateFunc        func(ctx context.Context) (hue.Datastore, error)
+	PairFunc             func(ctx context.Context, appName string, identifier string, opts *hue.PairOptions) error
+
+	// Group operations.
+	GroupsFunc         func(ctx context.Context) ([]hue.Group, error)
+	GroupFunc          func(ctx context.Context, id string) (hue.Group, error)
+	CreateGroupFunc    func(ctx context.Context, group *hue.Group) error
+	SetGroupFunc       func(ctx context.Context, id string, args *hue.GroupArg) error
+	SetGroupActionFunc func(ctx context.Context, id string, args *hue.GroupActionArg) error
+	DeleteGroupFunc    func(ctx context.Context, id string) error
+
+	// Scene operations.
+	ScenesFunc             func(ctx context.Context) ([]hue.Scene, error)
+	SceneFunc              func(ctx context.Context, id string) (hue.Scene, error)
+	CreateSceneFunc        func(ctx context.Context, scene *hue.Scene, lightStates map[string]*hue.LightStateArg) error
+	SetSceneFunc           func(ctx context.Context, id string, args *hue.SceneArg) error
+	SetSceneLightStateFunc func(ctx context.Context, id string, lightID string, args *hue.LightStateArg) error
+	DeleteSceneFunc        func(ctx context.Context, id string) error
+	RecallSceneFunc        func(ctx context.Context, groupID string, sceneID string) error
+
+	// Schedule operations.
+	SchedulesFunc      func(ctx context.Context) ([]hue.Schedule, error)
+	ScheduleFunc       func(ctx context.Context, id string) (hue.Schedule, error)
+	CreateScheduleFunc func(ctx context.Context, schedule *hue.Schedule) error
+	SetScheduleFunc    func(ctx context.Context, id string, args *hue.ScheduleArg) error
+	DeleteScheduleFunc func(ctx context.Context, id string) error
+
+	// Rule operations.
+	RulesFunc      func(ctx context.Context) ([]hue.Rule, error)
+	RuleFunc       func(ctx context.Context, id string) (hue.Rule, error)
+	CreateRuleFunc func(ctx context.Context, rule *hue.Rule) error
+	SetRuleFunc    func(ctx context.Context, id string, args *hue.RuleArg) error
+	DeleteRuleFunc func(ctx context.Context, id string) error
+
+	// Resource link operations.
+	ResourceLinksFunc      func(ctx context.Context) ([]hue.ResourceLink, error)
+	ResourceLinkFunc       func(ctx context.Context, id string) (hue.ResourceLink, error)
+	CreateResourceLinkFunc func(ctx context.Context, link *hue.ResourceLink) error
+	SetResourceLinkFunc    func(ctx context.Context, id string, args *hue.ResourceLinkArg) error
+	DeleteResourceLinkFunc func(ctx context.Context, id string) error
+	ResolveFunc            func(ctx context.Context, ref hue.ResourceReference) (interface{}, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Calls returns the calls made to the mock so far, in the order they were made.
+func (m *Bridge) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// CallsTo returns the calls made to the specified operation so far, in the order they were made.
+func (m *Bridge) CallsTo(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ret []Call
+	for _, call := range m.calls {
+		if call.Method == method {
+			ret = append(ret, call)
+		}
+	}
+
+	return ret
+}
+
+// ResetCalls forgets the calls made to the mock so far.
+func (m *Bridge) ResetCalls() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = nil
+}
+
+// record saves a call made to the mock.
+func (m *Bridge) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+// SearchForNewLights calls SearchForNewLightsWithContext with a background context.
+func (m *Bridge) SearchForNewLights(deviceIDs ...string) error {
+	return m.SearchForNewLightsWithContext(context.Background(), deviceIDs...)
+}
+
+// SearchForNewLightsWithContext records the call and returns the result of SearchForNewLightsFunc.
+func (m *Bridge) SearchForNewLightsWithContext(ctx context.Context, deviceIDs ...string) error {
+	m.record("SearchForNewLights", deviceIDs)
+
+	if m.SearchForNewLightsFunc != nil {
+		return m.SearchForNewLightsFunc(ctx, deviceIDs...)
+	}
+
+	return nil
+}
+
+// NewLights calls NewLightsWithContext with a background context.
+func (m *Bridge) NewLights() ([]hue.NewLight, hue.ScanStatus, error) {
+	return m.NewLightsWithContext(context.Background())
+}
+
+// NewLightsWithContext records the call and returns the result of NewLightsFunc.
+func (m *Bridge) NewLightsWithContext(ctx context.Context) ([]hue.NewLight, hue.ScanStatus, error) {
+	m.record("NewLights")
+
+	if m.NewLightsFunc != nil {
+		return m.NewLightsFunc(ctx)
+	}
+
+	return nil, hue.ScanStatus{}, nil
+}
+
+// Lights calls LightsWithContext with a background context.
+func (m *Bridge) Lights() ([]hue.Light, error) {
+	return m.LightsWithContext(context.Background())
+}
+
+// LightsWithContext records the call and returns the result of LightsFunc.
+func (m *Bridge) LightsWithContext(ctx context.Context) ([]hue.Light, error) {
+	m.record("Lights")
+
+	if m.LightsFunc != nil {
+		return m.LightsFunc(ctx)
+	}
+
+	return nil, nil
+}
+
+// Light calls LightWithContext with a background context.
+func (m *Bridge) Light(id string) (hue.Light, error) {
+	return m.LightWithContext(context.Background(), id)
+}
+
+// LightWithContext records the call and returns the result of LightFunc.
+func (m *Bridge) LightWithContext(ctx context.Context, id string) (hue.Light, error) {
+	m.record("Light", id)
+
+	if m.LightFunc != nil {
+		return m.LightFunc(ctx, id)
+	}
+
+	return hue.Light{}, nil
+}
+
+// SetLight calls SetLightWithContext with a background context.
+func (m *Bridge) SetLight(id string, args *hue.LightArg) error {
+	return m.SetLightWithContext(context.Background(), id, args)
+}
+
+// SetLightWithContext records the call and returns the result of SetLightFunc.
+func (m *Bridge) SetLightWithContext(ctx context.Context, id string, args *hue.LightArg) error {
+	m.record("SetLight", id, args)
+
+	if m.SetLightFunc != nil {
+		return m.SetLightFunc(ctx, id, args)
+	}
+
+	return nil
+}
+
+// SetLightState calls SetLightStateWithContext with a background context.
+func (m *Bridge) SetLightState(id string, args *hue.LightStateArg) error {
+	return m.SetLightStateWithContext(context.Background(), id, args)
+}
+
+// SetLightStateWithContext records the call and returns the result of SetLightStateFunc.
+func (m *Bridge) SetLightStateWithContext(ctx context.Context, id string, args *hue.LightStateArg) error {
+	m.record("SetLightState", id, args)
+
+	if m.SetLightStateFunc != nil {
+		return m.SetLightStateFunc(ctx, id, args)
+	}
+
+	return nil
+}
+
+// DeleteLight calls DeleteLightWithContext with a background context.
+func (m *Bridge) DeleteLight(id string) error {
+	return m.DeleteLightWithContext(context.Background(), id)
+}
+
+// DeleteLightWithContext records the call and returns the result of DeleteLightFunc.
+func (m *Bridge) DeleteLightWithContext(ctx context.Context, id string) error {
+	m.record("DeleteLight", id)
+
+	if m.DeleteLightFunc != nil {
+		return m.DeleteLightFunc(ctx, id)
+	}
+
+	return nil
+}
+
+// DeleteLightAndReferences calls DeleteLightAndReferencesWithContext with a background context.
+func (m *Bridge) DeleteLightAndReferences(id string) error {
+	return m.DeleteLightAndReferencesWithContext(context.Background(), id)
+}
+
+// DeleteLightAndReferencesWithContext records the call and returns the result of DeleteLightAndReferencesFunc.
+func (m *Bridge) DeleteLightAndReferencesWithContext(ctx context.Context, id string) error {
+	m.record("DeleteLightAndReferences", id)
+
+	if m.DeleteLightAndReferencesFunc != nil {
+		return m.DeleteLightAndReferencesFunc(ctx, id)
+	}
+
+	return nil
+}
+
+// SearchForNewSensors calls SearchForNewSensorsWithContext with a background context.
+func (m *Bridge) SearchForNewSensors(deviceIDs ...string) error {
+	return m.SearchForNewSensorsWithContext(context.Background(), deviceIDs...)
+}
+
+// SearchForNewSensorsWithContext records the call and returns the result of SearchForNewSensorsFunc.
+func (m *Bridge) SearchForNewSensorsWithContext(ctx context.Context, deviceIDs ...string) error {
+	m.record("SearchForNewSensors", deviceIDs)
+
+	if m.SearchForNewSensorsFunc != nil {
+		return m.SearchForNewSensorsFunc(ctx, deviceIDs...)
+	}
+
+	return nil
+}
+
+// NewSensors calls NewSensorsWithContext with a background context.
+func (m *Bridge) NewSensors() ([]hue.NewSensor, hue.ScanStatus, error) {
+	return m.NewSensorsWithContext(context.Background())
+}
+
+// NewSensorsWithContext records the call and returns the result of NewSensorsFunc.
+func (m *Bridge) NewSensorsWithContext(ctx context.Context) ([]hue.NewSensor, hue.ScanStatus, error) {
+	m.record("NewSensors")
+
+	if m.NewSensorsFunc != nil {
+		return m.NewSensorsFunc(ctx)
+	}
+
+	return nil, hue.ScanStatus{}, nil
+}
+
+// Sensors calls SensorsWithContext with a background context.
+func (m *Bridge) Sensors() ([]hue.Sensor, error) {
+	return m.SensorsWithContext(context.Background())
+}
+
+// SensorsWithContext records the call and returns the result of SensorsFunc.
+func (m *Bridge) SensorsWithContext(ctx context.Context) ([]hue.Sensor, error) {
+	m.record("Sensors")
+
+	if m.SensorsFunc != nil {
+		return m.SensorsFunc(ctx)
+	}
+
+	return nil, nil
+}
+
+// Sensor calls SensorWithContext with a background context.
+func (m *Bridge) Sensor(id string) (hue.Sensor, error) {
+	return m.SensorWithContext(context.Background(), id)
+}
+
+// SensorWithContext records the call and returns the result of SensorFunc.
+func (m *Bridge) SensorWithContext(ctx context.Context, id string) (hue.Sensor, error) {
+	m.record("Sensor", id)
+
+	if m.SensorFunc != nil {
+		return m.SensorFunc(ctx, id)
+	}
+
+	return hue.Sensor{}, nil
+}
+
+// SetSensor calls SetSensorWithContext with a background context.
+func (m *Bridge) SetSensor(id string, args *hue.SensorArg) error {
+	return m.SetSensorWithContext(context.Background(), id, args)
+}
+
+// SetSensorWithContext records the call and returns the result of SetSensorFunc.
+func (m *Bridge) SetSensorWithContext(ctx context.Context, id string, args *hue.SensorArg) error {
+	m.record("SetSensor", id, args)
+
+	if m.SetSensorFunc != nil {
+		return m.SetSensorFunc(ctx, id, args)
+	}
+
+	return nil
+}
+
+// SetSensorConfig calls SetSensorConfigWithContext with a background context.
+func (m *Bridge) SetSensorConfig(id string, args *hue.SensorConfigArg) error {
+	return m.SetSensorConfigWithContext(context.Background(), id, args)
+}
+
+// SetSensorConfigWithContext records the call and returns the result of SetSensorConfigFunc.
+func (m *Bridge) SetSensorConfigWithContext(ctx context.Context, id string, args *hue.SensorConfigArg) error {
+	m.record("SetSensorConfig", id, args)
+
+	if m.SetSensorConfigFunc != nil {
+		return m.SetSensorConfigFunc(ctx, id, args)
+	}
+
+	return nil
+}
+
+// SetSensorState calls SetSensorStateWithContext with a background context.
+func (m *Bridge) SetSensorState(id string, args *hue.SensorStateArg) error {
+	return m.SetSensorStateWithContext(context.Background(), id, args)
+}
+
+// SetSensorStateWithContext records the call and returns the result of SetSensorStateFunc.
+func (m *Bridge) SetSensorStateWithContext(ctx context.Context, id string, args *hue.SensorStateArg) error {
+	m.record("SetSensorState", id, args)
+
+	if m.SetSensorStateFunc != nil {
+		return m.SetSensorStateFunc(ctx, id, args)
+	}
+
+	return nil
+}
+
+// CreateSensor calls CreateSensorWithContext with a background context.
+func (m *Bridge) CreateSensor(sensor *hue.Sensor) error {
+	return m.CreateSensorWithContext(context.Background(), sensor)
+}
+
+// CreateSensorWithContext records the call and returns the result of CreateSensorFunc.
+func (m *Bridge) CreateSensorWithContext(ctx context.Context, sensor *hue.Sensor) error {
+	m.record("CreateSensor", sensor)
+
+	if m.CreateSensorFunc != nil {
+		return m.CreateSensorFunc(ctx, sensor)
+	}
+
+	return nil
+}
+
+// DeleteSensor calls DeleteSensorWithContext with a background context.
+func (m *Bridge) DeleteSensor(id string) error {
+	return m.DeleteSensorWithContext(context.Background(), id)
+}
+
+// DeleteSensorWithContext records the call and returns the result of DeleteSensorFunc.
+func (m *Bridge) DeleteSensorWithContext(ctx context.Context, id string) error {
+	m.record("DeleteSensor", id)
+
+	if m.DeleteSensorFunc != nil {
+		return m.DeleteSensorFunc(ctx, id)
+	}
+
+	return nil
+}
+
+// PairSensors records the call and returns the result of PairSensorsFunc.
+func (m *Bridge) PairSensors(ctx context.Context) ([]hue.Sensor, error) {
+	m.record("PairSensors")
+
+	if m.PairSensorsFunc != nil {
+		return m.PairSensorsFunc(ctx)
+	}
+
+	return nil, nil
+}
+
+// Config calls ConfigWithContext with a background context.
+func (m *Bridge) Config() (hue.Config, error) {
+	return m.ConfigWithContext(context.Background())
+}
+
+// ConfigWithContext records the call and returns the result of ConfigFunc.
+func (m *Bridge) ConfigWithContext(ctx context.Context) (hue.Config, error) {
+	m.record("Config")
+
+	if m.ConfigFunc != nil {
+		return m.ConfigFunc(ctx)
+	}
+
+	return hue.Config{}, nil
+}
+
+// SetConfig calls SetConfigWithContext with a background context.
+func (m *Bridge) SetConfig(args *hue.ConfigArg) error {
+	return m.SetConfigWithContext(context.Background(), args)
+}
+
+// SetConfigWithContext records the call and returns the result of SetConfigFunc.
+func (m *Bridge) SetConfigWithContext(ctx context.Context, args *hue.ConfigArg) error {
+	m.record("SetConfig", args)
+
+	if m.SetConfigFunc != nil {
+		return m.SetConfigFunc(ctx, args)
+	}
+
+	return nil
+}
+
+// DeleteUser calls DeleteUserWithContext with a background context.
+func (m *Bridge) DeleteUser(key string) error {
+	return m.DeleteUserWithContext(context.Background(), key)
+}
+
+// DeleteUserWithContext records the call and returns the result of DeleteUserFunc.
+func (m *Bridge) DeleteUserWithContext(ctx context.Context, key string) error {
+	m.record("DeleteUser", key)
+
+	if m.DeleteUserFunc != nil {
+		return m.DeleteUserFunc(ctx, key)
+	}
+
+	return nil
+}
+
+// RevokeStaleUsers calls RevokeStaleUsersWithContext with a background context.
+func (m *Bridge) RevokeStaleUsers(maxAge time.Duration) ([]string, error) {
+	return m.RevokeStaleUsersWithContext(context.Background(), maxAge)
+}
+
+// RevokeStaleUsersWithContext records the call and returns the result of RevokeStaleUsersFunc.
+func (m *Bridge) RevokeStaleUsersWithContext(ctx context.Context, maxAge time.Duration) ([]string, error) {
+	m.record("RevokeStaleUsers", maxAge)
+
+	if m.RevokeStaleUsersFunc != nil {
+		return m.RevokeStaleUsersFunc(ctx, maxAge)
+	}
+
+	return nil, nil
+}
+
+// CheckForUpdate calls CheckForUpdateWithContext with a background context.
+func (m *Bridge) CheckForUpdate() error {
+	return m.CheckForUpdateWithContext(context.Background())
+}
+
+// CheckForUpdateWithContext records the call and returns the result of CheckForUpdateFunc.
+func (m *Bridge) CheckForUpdateWithContext(ctx context.Context) error {
+	m.record("CheckForUpdate")
+
+	if m.CheckForUpdateFunc != nil {
+		return m.CheckForUpdateFunc(ctx)
+	}
+
+	return nil
+}
+
+// StartUpdate calls StartUpdateWithContext with a background context.
+func (m *Bridge) StartUpdate() error {
+	return m.StartUpdateWithContext(context.Background())
+}
+
+// StartUpdateWithContext records the call and returns the result of StartUpdateFunc.
+func (m *Bridge) StartUpdateWithContext(ctx context.Context) error {
+	m.record("StartUpdate")
+
+	if m.StartUpdateFunc != nil {
+		return m.StartUpdateFunc(ctx)
+	}
+
+	return nil
+}
+
+// FinishUpdate calls FinishUpdateWithContext with a background context.
+func (m *Bridge) FinishUpdate() error {
+	return m.FinishUpdateWithContext(context.Background())
+}
+
+// FinishUpdateWithContext records the call and returns the result of FinishUpdateFunc.
+func (m *Bridge) FinishUpdateWithContext(ctx context.Context) error {
+	m.record("FinishUpdate")
+
+	if m.FinishUpdateFunc != nil {
+		return m.FinishUpdateFunc(ctx)
+	}
+
+	return nil
+}
+
+// FullState calls FullStateWithContext with a background context.
+func (m *Bridge) FullState() (hue.Datastore, error) {
+	return m.FullStateWithContext(context.Background())
+}
+
+// FullStateWithContext records the call and returns the result of FullStateFunc.
+func (m *Bridge) FullStateWithContext(ctx context.Context) (hue.Datastore, error) {
+	m.record("FullState")
+
+	if m.FullStateFunc != nil {
+		return m.FullStateFunc(ctx)
+	}
+
+	return hue.Datastore{}, nil
+}
+
+// Pair calls PairWithContext with a background context and no options.
+func (m *Bridge) Pair(appName string, identifier string) error {
+	return m.PairWithContext(context.Background(), appName, identifier, nil)
+}
+
+// PairWithContext records the call and returns the result of PairFunc.
+func (m *Bridge) PairWithContext(ctx context.Context, appName string, identifier string, opts *hue.PairOptions) error {
+	m.record("Pair", appName, identifier, opts)
+
+	if m.PairFunc != nil {
+		return m.PairFunc(ctx, appName, identifier, opts)
+	}
+
+	return nil
+}
+
+// Groups calls GroupsWithContext with a background context.
+func (m *Bridge) Groups() ([]hue.Group, error) {
+	return m.GroupsWithContext(context.Background())
+}
+
+// GroupsWithContext records the call and returns the result of GroupsFunc.
+func (m *Bridge) GroupsWithContext(ctx context.Context) ([]hue.Group, error) {
+	m.record("Groups")
+
+	if m.GroupsFunc != nil {
+		return m.GroupsFunc(ctx)
+	}
+
+	return nil, nil
+}
+
+// Group calls GroupWithContext with a background context.
+func (m *Bridge) Group(id string) (hue.Group, error) {
+	return m.GroupWithContext(context.Background(), id)
+}
+
+// GroupWithContext records the call and returns the result of GroupFunc.
+func (m *Bridge) GroupWithContext(ctx context.Context, id string) (hue.Group, error) {
+	m.record("Group", id)
+
+	if m.GroupFunc != nil {
+		return m.GroupFunc(ctx, id)
+	}
+
+	return hue.Group{}, nil
+}
+
+// CreateGroup calls CreateGroupWithContext with a background context.
+func (m *Bridge) CreateGroup(group *hue.Group) error {
+	return m.CreateGroupWithContext(context.Background(), group)
+}
+
+// CreateGroupWithContext records the call and returns the result of CreateGroupFunc.
+func (m *Bridge) CreateGroupWithContext(ctx context.Context, group *hue.Group) error {
+	m.record("CreateGroup", group)
+
+	if m.CreateGroupFunc != nil {
+		return m.CreateGroupFunc(ctx, group)
+	}
+
+	return nil
+}
+
+// SetGroup calls SetGroupWithContext with a background context.
+func (m *Bridge) SetGroup(id string, args *hue.GroupArg) error {
+	return m.SetGroupWithContext(context.Background(), id, args)
+}
+
+// SetGroupWithContext records the call and returns the result of SetGroupFunc.
+func (m *Bridge) SetGroupWithContext(ctx context.Context, id string, args *hue.GroupArg) error {
+	m.record("SetGroup", id, args)
+
+	if m.SetGroupFunc != nil {
+		return m.SetGroupFunc(ctx, id, args)
+	}
+
+	return nil
+}
+
+// SetGroupAction calls SetGroupActionWithContext with a background context.
+func (m *Bridge) SetGroupAction(id string, args *hue.GroupActionArg) error {
+	return m.SetGroupActionWithContext(context.Background(), id, args)
+}
+
+// SetGroupActionWithContext records the call and returns the result of SetGroupActionFunc.
+func (m *Bridge) SetGroupActionWithContext(ctx context.Context, id string, args *hue.GroupActionArg) error {
+	m.record("SetGroupAction", id, args)
+
+	if m.SetGroupActionFunc != nil {
+		return m.SetGroupActionFunc(ctx, id, args)
+	}
+
+	return nil
+}
+
+// DeleteGroup calls DeleteGroupWithContext with a background context.
+func (m *Bridge) DeleteGroup(id string) error {
+	return m.DeleteGroupWithContext(context.Background(), id)
+}
+
+// DeleteGroupWithContext records the call and returns the result of DeleteGroupFunc.
+func (m *Bridge) DeleteGroupWithContext(ctx context.Context, id string) error {
+	m.record("DeleteGroup", id)
+
+	if m.DeleteGroupFunc != nil {
+		return m.DeleteGroupFunc(ctx, id)
+	}
+
+	return nil
+}
+
+// Scenes calls ScenesWithContext with a background context.
+func (m *Bridge) Scenes() ([]hue.Scene, error) {
+	return m.ScenesWithContext(context.Background())
+}
+
+// ScenesWithContext records the call and returns the result of ScenesFunc.
+func (m *Bridge) ScenesWithContext(ctx context.Context) ([]hue.Scene, error) {
+	m.record("Scenes")
+
+	if m.ScenesFunc != nil {
+		return m.ScenesFunc(ctx)
+	}
+
+	return nil, nil
+}
+
+// Scene calls SceneWithContext with a background context.
+func (m *Bridge) Scene(id string) (hue.Scene, error) {
+	return m.SceneWithContext(context.Background(), id)
+}
+
+// SceneWithContext records the call and returns the result of SceneFunc.
+func (m *Bridge) SceneWithContext(ctx context.Context, id string) (hue.Scene, error) {
+	m.record("Scene", id)
+
+	if m.SceneFunc != nil {
+		return m.SceneFunc(ctx, id)
+	}
+
+	return hue.Scene{}, nil
+}
+
+// CreateScene calls CreateSceneWithContext with a background context.
+func (m *Bridge) CreateScene(scene *hue.Scene, lightStates map[string]*hue.LightStateArg) error {
+	return m.CreateSceneWithContext(context.Background(), scene, lightStates)
+}
+
+// CreateSceneWithContext records the call and returns the result of CreateSceneFunc.
+func (m *Bridge) CreateSceneWithContext(ctx context.Context, scene *hue.Scene, lightStates map[string]*hue.LightStateArg) error {
+	m.record("CreateScene", scene, lightStates)
+
+	if m.CreateSceneFunc != nil {
+		return m.CreateSceneFunc(ctx, scene, lightStates)
+	}
+
+	return nil
+}
+
+// SetScene calls SetSceneWithContext with a background context.
+func (m *Bridge) SetScene(id string, args *hue.SceneArg) error {
+	return m.SetSceneWithContext(context.Background(), id, args)
+}
+
+// SetSceneWithContext records the call and returns the result of SetSceneFunc.
+func (m *Bridge) SetSceneWithContext(ctx context.Context, id string, args *hue.SceneArg) error {
+	m.record("SetScene", id, args)
+
+	if m.SetSceneFunc != nil {
+		return m.SetSceneFunc(ctx, id, args)
+	}
+
+	return nil
+}
+
+// SetSceneLightState calls SetSceneLightStateWithContext with a background context.
+func (m *Bridge) SetSceneLightState(id string, lightID string, args *hue.LightStateArg) error {
+	return m.SetSceneLightStateWithContext(context.Background(), id, lightID, args)
+}
+
+// SetSceneLightStateWithContext records the call and returns the result of SetSceneLightStateFunc.
+func (m *Bridge) SetSceneLightStateWithContext(ctx context.Context, id string, lightID string, args *hue.LightStateArg) error {
+	m.record("SetSceneLightState", id, lightID, args)
+
+	if m.SetSceneLightStateFunc != nil {
+		return m.SetSceneLightStateFunc(ctx, id, lightID, args)
+	}
+
+	return nil
+}
+
+// DeleteScene calls DeleteSceneWithContext with a background context.
+func (m *Bridge) DeleteScene(id string) error {
+	return m.DeleteSceneWithContext(context.Background(), id)
+}
+
+// DeleteSceneWithContext records the call and returns the result of DeleteSceneFunc.
+func (m *Bridge) DeleteSceneWithContext(ctx context.Context, id string) error {
+	m.record("DeleteScene", id)
+
+	if m.DeleteSceneFunc != nil {
+		return m.DeleteSceneFunc(ctx, id)
+	}
+
+	return nil
+}
+
+// RecallScene calls RecallSceneWithContext with a background context.
+func (m *Bridge) RecallScene(groupID string, sceneID string) error {
+	return m.RecallSceneWithContext(context.Background(), groupID, sceneID)
+}
+
+// RecallSceneWithContext records the call and returns the result of RecallSceneFunc.
+func (m *Bridge) RecallSceneWithContext(ctx context.Context, groupID string, sceneID string) error {
+	m.record("RecallScene", groupID, sceneID)
+
+	if m.RecallSceneFunc != nil {
+		return m.RecallSceneFunc(ctx, groupID, sceneID)
+	}
+
+	return nil
+}
+
+// Schedules calls SchedulesWithContext with a background context.
+func (m *Bridge) Schedules() ([]hue.Schedule, error) {
+	return m.SchedulesWithContext(context.Background())
+}
+
+// SchedulesWithContext records the call and returns the result of SchedulesFunc.
+func (m *Bridge) SchedulesWithContext(ctx context.Context) ([]hue.Schedule, error) {
+	m.record("Schedules")
+
+	if m.SchedulesFunc != nil {
+		return m.SchedulesFunc(ctx)
+	}
+
+	return nil, nil
+}
+
+// Schedule calls ScheduleWithContext with a background context.
+func (m *Bridge) Schedule(id string) (hue.Schedule, error) {
+	return m.ScheduleWithContext(context.Background(), id)
+}
+
+// ScheduleWithContext records the call and returns the result of ScheduleFunc.
+func (m *Bridge) ScheduleWithContext(ctx context.Context, id string) (hue.Schedule, error) {
+	m.record("Schedule", id)
+
+	if m.ScheduleFunc != nil {
+		return m.ScheduleFunc(ctx, id)
+	}
+
+	return hue.Schedule{}, nil
+}
+
+// CreateSchedule calls CreateScheduleWithContext with a background context.
+func (m *Bridge) CreateSchedule(schedule *hue.Schedule) error {
+	return m.CreateScheduleWithContext(context.Background(), schedule)
+}
+
+// CreateScheduleWithContext records the call and returns the result of CreateScheduleFunc.
+func (m *Bridge) CreateScheduleWithContext(ctx context.Context, schedule *hue.Schedule) error {
+	m.record("CreateSchedule", schedule)
+
+	if m.CreateScheduleFunc != nil {
+		return m.CreateScheduleFunc(ctx, schedule)
+	}
+
+	return nil
+}
+
+// SetSchedule calls SetScheduleWithContext with a background context.
+func (m *Bridge) SetSchedule(id string, args *hue.ScheduleArg) error {
+	return m.SetScheduleWithContext(context.Background(), id, args)
+}
+
+// SetScheduleWithContext records the call and returns the result of SetScheduleFunc.
+func (m *Bridge) SetScheduleWithContext(ctx context.Context, id string, args *hue.ScheduleArg) error {
+	m.record("SetSchedule", id, args)
+
+	if m.SetScheduleFunc != nil {
+		return m.SetScheduleFunc(ctx, id, args)
+	}
+
+	return nil
+}
+
+// DeleteSchedule calls DeleteScheduleWithContext with a background context.
+func (m *Bridge) DeleteSchedule(id string) error {
+	return m.DeleteScheduleWithContext(context.Background(), id)
+}
+
+// DeleteScheduleWithContext records the call and returns the result of DeleteScheduleFunc.
+func (m *Bridge) DeleteScheduleWithContext(ctx context.Context, id string) error {
+	m.record("DeleteSchedule", id)
+
+	if m.DeleteScheduleFunc != nil {
+		return m.DeleteScheduleFunc(ctx, id)
+	}
+
+	return nil
+}
+
+// Rules calls RulesWithContext with a background context.
+func (m *Bridge) Rules() ([]hue.Rule, error) {
+	return m.RulesWithContext(context.Background())
+}
+
+// RulesWithContext records the call and returns the result of RulesFunc.
+func (m *Bridge) RulesWithContext(ctx context.Context) ([]hue.Rule, error) {
+	m.record("Rules")
+
+	if m.RulesFunc != nil {
+		return m.RulesFunc(ctx)
+	}
+
+	return nil, nil
+}
+
+// Rule calls RuleWithContext with a background context.
+func (m *Bridge) Rule(id string) (hue.Rule, error) {
+	return m.RuleWithContext(context.Background(), id)
+}
+
+// RuleWithContext records the call and returns the result of RuleFunc.
+func (m *Bridge) RuleWithContext(ctx context.Context, id string) (hue.Rule, error) {
+	m.record("Rule", id)
+
+	if m.RuleFunc != nil {
+		return m.RuleFunc(ctx, id)
+	}
+
+	return hue.Rule{}, nil
+}
+
+// CreateRule calls CreateRuleWithContext with a background context.
+func (m *Bridge) CreateRule(rule *hue.Rule) error {
+	return m.CreateRuleWithContext(context.Background(), rule)
+}
+
+// CreateRuleWithContext records the call and returns the result of CreateRuleFunc.
+func (m *Bridge) CreateRuleWithContext(ctx context.Context, rule *hue.Rule) error {
+	m.record("CreateRule", rule)
+
+	if m.CreateRuleFunc != nil {
+		return m.CreateRuleFunc(ctx, rule)
+	}
+
+	return nil
+}
+
+// SetRule calls SetRuleWithContext with a background context.
+func (m *Bridge) SetRule(id string, args *hue.RuleArg) error {
+	return m.SetRuleWithContext(context.Background(), id, args)
+}
+
+// SetRuleWithContext records the call and returns the result of SetRuleFunc.
+func (m *Bridge) SetRuleWithContext(ctx context.Context, id string, args *hue.RuleArg) error {
+	m.record("SetRule", id, args)
+
+	if m.SetRuleFunc != nil {
+		return m.SetRuleFunc(ctx, id, args)
+	}
+
+	return nil
+}
+
+// DeleteRule calls DeleteRuleWithContext with a background context.
+func (m *Bridge) DeleteRule(id string) error {
+	return m.DeleteRuleWithContext(context.Background(), id)
+}
+
+// DeleteRuleWithContext records the call and returns the result of DeleteRuleFunc.
+func (m *Bridge) DeleteRuleWithContext(ctx context.Context, id string) error {
+	m.record("DeleteRule", id)
+
+	if m.DeleteRuleFunc != nil {
+		return m.DeleteRuleFunc(ctx, id)
+	}
+
+	return nil
+}
+
+// ResourceLinks calls ResourceLinksWithContext with a background context.
+func (m *Bridge) ResourceLinks() ([]hue.ResourceLink, error) {
+	return m.ResourceLinksWithContext(context.Background())
+}
+
+// ResourceLinksWithContext records the call and returns the result of ResourceLinksFunc.
+func (m *Bridge) ResourceLinksWithContext(ctx context.Context) ([]hue.ResourceLink, error) {
+	m.record("ResourceLinks")
+
+	if m.ResourceLinksFunc != nil {
+		return m.ResourceLinksFunc(ctx)
+	}
+
+	return nil, nil
+}
+
+// ResourceLink calls ResourceLinkWithContext with a background context.
+func (m *Bridge) ResourceLink(id string) (hue.ResourceLink, error) {
+	return m.ResourceLinkWithContext(context.Background(), id)
+}
+
+// ResourceLinkWithContext records the call and returns the result of ResourceLinkFunc.
+func (m *Bridge) ResourceLinkWithContext(ctx context.Context, id string) (hue.ResourceLink, error) {
+	m.record("ResourceLink", id)
+
+	if m.ResourceLinkFunc != nil {
+		return m.ResourceLinkFunc(ctx, id)
+	}
+
+	return hue.ResourceLink{}, nil
+}
+
+// CreateResourceLink calls CreateResourceLinkWithContext with a background context.
+func (m *Bridge) CreateResourceLink(link *hue.ResourceLink) error {
+	return m.CreateResourceLinkWithContext(context.Background(), link)
+}
+
+// CreateResourceLinkWithContext records the call and returns the result of CreateResourceLinkFunc.
+func (m *Bridge) CreateResourceLinkWithContext(ctx context.Context, link *hue.ResourceLink) error {
+	m.record("CreateResourceLink", link)
+
+	if m.CreateResourceLinkFunc != nil {
+		return m.CreateResourceLinkFunc(ctx, link)
+	}
+
+	return nil
+}
+
+// SetResourceLink calls SetResourceLinkWithContext with a background context.
+func (m *Bridge) SetResourceLink(id string, args *hue.ResourceLinkArg) error {
+	return m.SetResourceLinkWithContext(context.Background(), id, args)
+}
+
+// SetResourceLinkWithContext records the call and returns the result of SetResourceLinkFunc.
+func (m *Bridge) SetResourceLinkWithContext(ctx context.Context, id string, args *hue.ResourceLinkArg) error {
+	m.record("SetResourceLink", id, args)
+
+	if m.SetResourceLinkFunc != nil {
+		return m.SetResourceLinkFunc(ctx, id, args)
+	}
+
+	return nil
+}
+
+// DeleteResourceLink calls DeleteResourceLinkWithContext with a background context.
+func (m *Bridge) DeleteResourceLink(id string) error {
+	return m.DeleteResourceLinkWithContext(context.Background(), id)
+}
+
+// DeleteResourceLinkWithContext records the call and returns the result of DeleteResourceLinkFunc.
+func (m *Bridge) DeleteResourceLinkWithContext(ctx context.Context, id string) error {
+	m.record("DeleteResourceLink", id)
+
+	if m.DeleteResourceLinkFunc != nil {
+		return m.DeleteResourceLinkFunc(ctx, id)
+	}
+
+	return nil
+}
+
+// Resolve calls ResolveWithContext with a background context.
+func (m *Bridge) Resolve(ref hue.ResourceReference) (interface{}, error) {
+	return m.ResolveWithContext(context.Background(), ref)
+}
+
+// ResolveWithContext records the call and returns the result of ResolveFunc.
+func (m *Bridge) ResolveWithContext(ctx context.Context, ref hue.ResourceReference) (interface{}, error) {
+	m.record("Resolve", ref)
+
+	if m.ResolveFunc != nil {
+		return m.ResolveFunc(ctx, ref)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,97 @@
+package huemock
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	hue "github.com/rmrobinson/hue-go"
+)
+
+// turnOn is an example of code under test which depends on the interface rather than a *hue.Bridge.
+func turnOn(api hue.LightAPI, id string) error {
+	var args hue.LightStateArg
+	args.SetIsOn(true)
+
+	return api.SetLightState(id, &args)
+}
+
+func TestBridge_Calls(t *testing.T) {
+	m := &Bridge{}
+
+	var received *hue.LightStateArg
+	m.SetLightStateFunc = func(ctx context.Context, id string, args *hue.LightStateArg) error {
+		received = args
+		return nil
+	}
+
+	if err := turnOn(m, "1"); err != nil {
+		t.Fatalf("Unable to set light state: %s\n", err.Error())
+	}
+	if received == nil || !received.IsOn() {
+		t.Errorf("Expected the light to be turned on, got %+v\n", received)
+	}
+
+	calls := m.CallsTo("SetLightState")
+	if len(calls) != 1 || calls[0].Args[0] != "1" {
+		t.Errorf("Unexpected calls recorded: %+v\n", calls)
+	}
+
+	m.ResetCalls()
+	if len(m.Calls()) != 0 {
+		t.Errorf("Expected no calls after resetting, got %+v\n", m.Calls())
+	}
+}
+
+func TestBridge_Defaults(t *testing.T) {
+	m := &Bridge{}
+
+	if lights, err := m.Lights(); err != nil || lights != nil {
+		t.Errorf("Expected no lights and no error, got %+v, %v\n", lights, err)
+	}
+	if err := m.StartUpdate(); err != nil {
+		t.Errorf("Expected no error, got %s\n", err.Error())
+	}
+	if len(m.Calls()) != 2 {
+		t.Errorf("Expected 2 calls to be recorded, got %+v\n", m.Calls())
+	}
+}
+
+func TestBridge_Pair(t *testing.T) {
+	m := &Bridge{}
+
+	var received *hue.PairOptions
+	m.PairFunc = func(ctx context.Context, appName string, identifier string, opts *hue.PairOptions) error {
+		received = opts
+		return hue.ErrLinkButtonNotPressed
+	}
+
+	if err := m.Pair("app", "device"); !errors.Is(err, hue.ErrLinkButtonNotPressed) {
+		t.Errorf("Expected link button not pressed, got %v\n", err)
+	}
+	if received != nil {
+		t.Errorf("Expected no pairing options, got %+v\n", received)
+	}
+
+	calls := m.CallsTo("Pair")
+	if len(calls) != 1 || calls[0].Args[0] != "app" || calls[0].Args[1] != "device" {
+		t.Errorf("Unexpected calls recorded: %+v\n", calls)
+	}
+}
+
+func TestBridge_ServeLights(t *testing.T) {
+	m := &Bridge{}
+	m.ServeLights(hue.Light{ID: "1", Name: "Hue lamp 1"})
+
+	lights, err := m.Lights()
+	if err != nil || len(lights) != 1 {
+		t.Fatalf("Unexpected lights returned: %+v, %v\n", lights, err)
+	}
+
+	if light, err := m.Light("1"); err != nil || light.Name != "Hue lamp 1" {
+		t.Errorf("Unexpected light returned: %+v, %v\n", light, err)
+	}
+	if _, err := m.Light("2"); !errors.Is(err, hue.ErrResourceNotAvailable) {
+		t.Errorf("Expected the light to not be available, got %v\n", err)
+	}
+}
@@ -0,0 +1,48 @@
+package huemock
+
+import (
+	"context"
+
+	hue "github.com/rmrobinson/hue-go"
+)
+
+// ServeLights sets LightsFunc and LightFunc to return the supplied lights.
+// Light reports hue.ErrResourceNotAvailable for an ID which isn't one of the supplied lights.
+func (m *Bridge) ServeLights(lights ...hue.Light) {
+	m.LightsFunc = func(ctx context.Context) ([]hue.Light, error) {
+		return append([]hue.Light(nil), lights...), nil
+	}
+	m.LightFunc = func(ctx context.Context, id string) (hue.Light, error) {
+		for _, light := range lights {
+			if light.ID == id {
+				return light, nil
+			}
+		}
+
+		return hue.Light{}, hue.ErrResourceNotAvailable
+	}
+}
+
+// ServeSensors sets SensorsFunc and SensorFunc to return the supplied sensors.
+// Sensor reports hue.ErrResourceNotAvailable for an ID which isn't one of the supplied sensors.
+func (m *Bridge) ServeSensors(sensors ...hue.Sensor) {
+	m.SensorsFunc = func(ctx context.Context) ([]hue.Sensor, error) {
+		return append([]hue.Sensor(nil), sensors...), nil
+	}
+	m.SensorFunc = func(ctx context.Context, id string) (hue.Sensor, error) {
+		for _, sensor := range sensors {
+			if sensor.ID == id {
+				return sensor, nil
+			}
+		}
+
+		return hue.Sensor{}, hue.ErrResourceNotAvailable
+	}
+}
+
+// ServeConfig sets ConfigFunc to return the supplied config.
+func (m *Bridge) ServeConfig(config hue.Config) {
+	m.ConfigFunc = func(ctx context.Context) (hue.Config, error) {
+		return config, nil
+	}
+}
@@ -0,0 +1,349 @@
+package huetest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	hue "github.com/rmrobinson/hue-go"
+	"github.com/rmrobinson/hue-go/internal/bridgeapi"
+)
+
+// testClientKey is the client key returned to pairing requests which ask for one.
+const testClientKey = "0123456789ABCDEF0123456789ABCDEF"
+
+// nameKeys restricts a resource to only having its name changed.
+var nameKeys = map[string]bool{
+	"name": true,
+}
+
+// emptyCollections are the resource collections which the fake bridge reports, but does not store anything in.
+var emptyCollections = bridgeapi.EmptyCollections()
+
+// storedKeys are the kinds of resource which the fake bridge stores without any special handling, along with the
+// values which can be changed on each.
+var storedKeys = map[string]map[string]bool{
+	"groups": {"name": true, "lights": true, "class": true},
+	"scenes": {"name": true, "lights": true},
+	"rules":  {"name": true, "status": true, "conditions": true, "actions": true},
+}
+
+func (s *Server) handleDescription(w http.ResponseWriter, r *http.Request) {
+	config := s.Config()
+
+	bridgeapi.WriteDescription(w, s.URL, config.Name, config.ID, "2f402f80-da50-11e1-9b23-"+config.ID)
+}
+
+func (s *Server) handleAPI(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	latency := s.latency
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(latency):
+		}
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := strings.SplitN(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api"), "/"), "/", 2)
+	username := parts[0]
+
+	path := ""
+	if len(parts) > 1 {
+		path = "/" + parts[1]
+	}
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   path,
+		Body:   body,
+	})
+
+	if len(username) < 1 {
+		if r.Method != http.MethodPost {
+			bridgeapi.WriteJSON(w, bridgeapi.ErrorEntries(4, "/", "method, "+r.Method+", not available for resource, /"))
+			return
+		}
+
+		bridgeapi.WriteJSON(w, s.pair(body))
+		return
+	} else if username != s.username {
+		bridgeapi.WriteJSON(w, bridgeapi.ErrorEntries(1, "/", "unauthorized user"))
+		return
+	} else if e, ok := s.injected[r.Method+" "+path]; ok {
+		bridgeapi.WriteJSON(w, []interface{}{map[string]interface{}{"error": e}})
+		return
+	}
+
+	bridgeapi.WriteJSON(w, s.route(r.Method, path, body))
+}
+
+// route handles a single API request; the caller must hold the lock.
+func (s *Server) route(method string, path string, body []byte) interface{} {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	switch {
+	case path == "" && method == http.MethodGet:
+		return s.fullState()
+	case segments[0] == "config":
+		if len(segments) == 1 && method == http.MethodGet {
+			return s.config
+		} else if len(segments) == 1 && method == http.MethodPut {
+			return applyValues(&s.config, body, path, nil)
+		}
+	case segments[0] == "lights":
+		return s.routeLights(method, path, segments[1:], body)
+	case segments[0] == "sensors":
+		return s.routeSensors(method, path, segments[1:], body)
+	case storedKeys[segments[0]] != nil:
+		return s.routeStored(method, path, segments, body)
+	case emptyCollections[segments[0]] && len(segments) == 1 && method == http.MethodGet:
+		return map[string]interface{}{}
+	default:
+		return bridgeapi.ErrorEntries(3, path, "resource, "+path+", not available")
+	}
+
+	return bridgeapi.ErrorEntries(4, path, "method, "+method+", not available for resource, "+path)
+}
+
+func (s *Server) routeLights(method string, path string, segments []string, body []byte) interface{} {
+	if len(segments) == 0 {
+		if method == http.MethodGet {
+			return s.lights
+		} else if method == http.MethodPost {
+			return bridgeapi.SuccessEntries("/lights", "Searching for new devices")
+		}
+	} else if segments[0] == "new" && len(segments) == 1 {
+		if method == http.MethodGet {
+			return map[string]string{"lastscan": "none"}
+		}
+	} else if light, ok := s.lights[segments[0]]; !ok {
+		return bridgeapi.ErrorEntries(3, path, "resource, "+path+", not available")
+	} else if len(segments) == 1 {
+		if method == http.MethodGet {
+			return light
+		} else if method == http.MethodPut {
+			return applyValues(light, body, path, nameKeys)
+		} else if method == http.MethodDelete {
+			delete(s.lights, light.ID)
+			return bridgeapi.SuccessEntries("", path+" deleted")
+		}
+	} else if segments[1] == "state" && len(segments) == 2 {
+		if method == http.MethodPut {
+			return s.setLightState(light, body, path)
+		}
+	} else {
+		return bridgeapi.ErrorEntries(3, path, "resource, "+path+", not available")
+	}
+
+	return bridgeapi.ErrorEntries(4, path, "method, "+method+", not available for resource, "+path)
+}
+
+func (s *Server) routeSensors(method string, path string, segments []string, body []byte) interface{} {
+	if len(segments) == 0 {
+		if method == http.MethodGet {
+			return s.sensors
+		} else if method == http.MethodPost {
+			return s.createSensor(body)
+		}
+	} else if segments[0] == "new" && len(segments) == 1 {
+		if method == http.MethodGet {
+			return map[string]string{"lastscan": "none"}
+		}
+	} else if sensor, ok := s.sensors[segments[0]]; !ok {
+		return bridgeapi.ErrorEntries(3, path, "resource, "+path+", not available")
+	} else if len(segments) == 1 {
+		if method == http.MethodGet {
+			return sensor
+		} else if method == http.MethodPut {
+			return applyValues(sensor, body, path, nameKeys)
+		} else if method == http.MethodDelete {
+			delete(s.sensors, sensor.ID)
+			return bridgeapi.SuccessEntries("", path+" deleted")
+		}
+	} else if segments[1] == "config" && len(segments) == 2 {
+		if method == http.MethodPut {
+			return applyValues(&sensor.Config, body, path, nil)
+		}
+	} else if segments[1] == "state" && len(segments) == 2 {
+		if method == http.MethodPut {
+			return applyValues(&sensor.State, body, path, nil)
+		}
+	} else {
+		return bridgeapi.ErrorEntries(3, path, "resource, "+path+", not available")
+	}
+
+	return bridgeapi.ErrorEntries(4, path, "method, "+method+", not available for resource, "+path)
+}
+
+// routeStored handles a request for a group, scene or rule; the caller must hold the lock.
+func (s *Server) routeStored(method string, path string, segments []string, body []byte) interface{} {
+	resources := s.stored[segments[0]]
+
+	if len(segments) == 1 {
+		if method == http.MethodGet {
+			return resources
+		}
+	} else if resource, ok := resources[segments[1]]; !ok {
+		return bridgeapi.ErrorEntries(3, path, "resource, "+path+", not available")
+	} else if len(segments) == 2 {
+		if method == http.MethodGet {
+			return resource
+		} else if method == http.MethodPut {
+			return applyValues(resource, body, path, storedKeys[segments[0]])
+		} else if method == http.MethodDelete {
+			delete(resources, segments[1])
+			return bridgeapi.SuccessEntries("", path+" deleted")
+		}
+	} else {
+		return bridgeapi.ErrorEntries(3, path, "resource, "+path+", not available")
+	}
+
+	return bridgeapi.ErrorEntries(4, path, "method, "+method+", not available for resource, "+path)
+}
+
+// pair handles a request to create a new user; the caller must hold the lock.
+func (s *Server) pair(body []byte) interface{} {
+	var req struct {
+		DeviceType        string `json:"devicetype"`
+		GenerateClientKey bool   `json:"generateclientkey"`
+	}
+
+	if err := json.Unmarshal(body, &req); err != nil {
+		return bridgeapi.ErrorEntries(2, "", "body contains invalid json")
+	} else if len(req.DeviceType) < 1 {
+		return bridgeapi.ErrorEntries(5, "/devicetype", "invalid/missing parameters in body")
+	} else if !s.config.LinkButton {
+		return bridgeapi.ErrorEntries(101, "", "link button not pressed")
+	}
+
+	if s.config.Whitelist == nil {
+		s.config.Whitelist = make(map[string]hue.WhitelistEntry)
+	}
+	s.config.Whitelist[s.username] = hue.WhitelistEntry{Name: req.DeviceType}
+
+	success := map[string]string{"username": s.username}
+	if req.GenerateClientKey {
+		success["clientkey"] = testClientKey
+	}
+
+	return []interface{}{map[string]interface{}{"success": success}}
+}
+
+// setLightState handles a change to the state of a light; the caller must hold the lock.
+func (s *Server) setLightState(light *hue.Light, body []byte, path string) interface{} {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(body, &values); err != nil {
+		return bridgeapi.ErrorEntries(2, path, "body contains invalid json")
+	}
+
+	// A real bridge rejects changes to a light which is off, unless the same request turns it on.
+	var turnOn bool
+	if v, ok := values["on"]; ok {
+		json.Unmarshal(v, &turnOn)
+	}
+
+	bridgeapi.ApplyIncrements(light.State, values)
+
+	state := light.State
+	if s.unreachable[light.ID] {
+		// The changes are acknowledged by the bridge, but never reach the light.
+		state = hue.LightState{}
+	}
+
+	var entries []interface{}
+	filtered := make(map[string]json.RawMessage)
+
+	for _, key := range bridgeapi.SortedKeys(values) {
+		if key == "transitiontime" {
+			entries = append(entries, bridgeapi.SuccessEntry(path+"/"+key, values[key]))
+		} else if key != "on" && bridgeapi.LightStateKeys[key] && !light.State.On && !turnOn {
+			entries = append(entries, bridgeapi.ErrorEntry(201, path+"/"+key, "parameter, "+key+", is not modifiable. Device is set to off."))
+		} else {
+			filtered[key] = values[key]
+		}
+	}
+
+	applied, _ := bridgeapi.ApplyValues(&state, filtered, path, bridgeapi.LightStateKeys)
+	entries = append(entries, applied...)
+
+	if !s.unreachable[light.ID] {
+		if _, ok := filtered["xy"]; ok {
+			state.ColorMode = "xy"
+		} else if _, ok := filtered["ct"]; ok {
+			state.ColorMode = "ct"
+		} else if _, ok := filtered["hue"]; ok {
+			state.ColorMode = "hs"
+		} else if _, ok := filtered["sat"]; ok {
+			state.ColorMode = "hs"
+		}
+
+		light.State = state
+	}
+
+	return entries
+}
+
+// createSensor handles a request to add a software sensor; the caller must hold the lock.
+func (s *Server) createSensor(body []byte) interface{} {
+	// A search request has no body, or only contains the device IDs to search for.
+	var values map[string]json.RawMessage
+	if len(body) < 1 || json.Unmarshal(body, &values) == nil && values["name"] == nil {
+		return bridgeapi.SuccessEntries("/sensors", "Searching for new devices")
+	}
+
+	var sensor hue.Sensor
+	if err := json.Unmarshal(body, &sensor); err != nil {
+		return bridgeapi.ErrorEntries(2, "/sensors", "body contains invalid json")
+	} else if len(sensor.Name) < 1 || len(sensor.Type) < 1 {
+		return bridgeapi.ErrorEntries(5, "/sensors", "invalid/missing parameters in body")
+	}
+
+	sensor.ID = s.allocateID("sensors")
+	s.sensors[sensor.ID] = &sensor
+
+	return bridgeapi.SuccessEntries("id", sensor.ID)
+}
+
+// fullState returns the complete datastore of the fake bridge; the caller must hold the lock.
+func (s *Server) fullState() interface{} {
+	state := map[string]interface{}{
+		"config":  s.config,
+		"lights":  s.lights,
+		"sensors": s.sensors,
+	}
+
+	for kind := range emptyCollections {
+		state[kind] = map[string]interface{}{}
+	}
+	for kind, resources := range s.stored {
+		state[kind] = resources
+	}
+
+	return state
+}
+
+// applyValues sets each value in the request body on the supplied resource and returns the response entries.
+// Values which the resource doesn't have, or which aren't in the allowed keys if specified, are rejected.
+func applyValues(resource interface{}, body []byte, path string, allowed map[string]bool) []interface{} {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(body, &values); err != nil {
+		return bridgeapi.ErrorEntries(2, path, "body contains invalid json")
+	}
+
+	entries, _ := bridgeapi.ApplyValues(resource, values, path, allowed)
+	return entries
+}
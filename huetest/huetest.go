@@ -0,0 +1,367 @@
+// Package huetest provides a fake Hue bridge for testing code which uses the hue package without real hardware.
+//
+// The fake bridge serves the bridge description along with the lights, sensors, groups, scenes, rules and config portions
+// of the v1 REST API, backed by an in-memory datastore which is updated by the requests it receives.
+package huetest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	hue "github.com/rmrobinson/hue-go"
+)
+
+// DefaultUsername is the username the fake bridge accepts unless another is configured with SetUsername.
+const DefaultUsername = "huetest"
+
+// DefaultBridgeID is the ID the fake bridge reports in its description and config.
+const DefaultBridgeID = "001788fffe100491"
+
+// Request is a single API request received by the fake bridge.
+type Request struct {
+	Method string
+	// Path is relative to the username, such as /lights/1/state.
+	Path string
+	Body []byte
+}
+
+// Server is a fake Hue bridge served over HTTP.
+// All methods are safe to call while the bridge is handling requests.
+type Server struct {
+	// URL is the base URL of the server, such as http://127.0.0.1:1234.
+	URL string
+
+	srv *httptest.Server
+
+	mu sync.Mutex
+
+	username string
+	config   hue.Config
+	lights   map[string]*hue.Light
+	sensors  map[string]*hue.Sensor
+	// stored holds the groups, scenes and rules, keyed by the kind of resource and then the ID.
+	stored  map[string]map[string]interface{}
+	nextIDs map[string]int
+
+	unreachable map[string]bool
+	injected    map[string]hue.ResponseError
+	latency     time.Duration
+
+	requests []Request
+}
+
+// NewServer starts a fake bridge with an empty datastore.
+// The caller should call Close when finished to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		username:    DefaultUsername,
+		lights:      make(map[string]*hue.Light),
+		sensors:     make(map[string]*hue.Sensor),
+		stored:      make(map[string]map[string]interface{}),
+		nextIDs:     make(map[string]int),
+		unreachable: make(map[string]bool),
+		injected:    make(map[string]hue.ResponseError),
+	}
+
+	s.config.Name = "Hue test bridge"
+	s.config.ID = DefaultBridgeID
+	s.config.SwVersion = "1935144040"
+	s.config.APIVersion = "1.35.0"
+	s.config.ModelVersion = "BSB002"
+	s.config.Whitelist = map[string]hue.WhitelistEntry{
+		DefaultUsername: {Name: "huetest#default"},
+	}
+
+	for kind := range storedKeys {
+		s.stored[kind] = make(map[string]interface{})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/description.xml", s.handleDescription)
+	mux.HandleFunc("/api", s.handleAPI)
+	mux.HandleFunc("/api/", s.handleAPI)
+
+	s.srv = httptest.NewServer(mux)
+	s.URL = s.srv.URL
+
+	return s
+}
+
+// Close shuts down the fake bridge.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Bridge returns a bridge which has been initialized against the fake bridge and uses its username.
+func (s *Server) Bridge(opts ...hue.BridgeOption) (*hue.Bridge, error) {
+	b := hue.NewBridge(s.Username(), opts...)
+
+	if err := b.InitIP(s.srv.Listener.Addr().String()); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Username returns the username the fake bridge accepts.
+func (s *Server) Username() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.username
+}
+
+// SetUsername changes the username the fake bridge accepts; requests using any other username are rejected as unauthorized.
+func (s *Server) SetUsername(username string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.username = username
+}
+
+// PressLinkButton allows pairing requests to succeed; they are rejected with hue.ErrLinkButtonNotPressed until it is called.
+func (s *Server) PressLinkButton() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config.LinkButton = true
+}
+
+// Config returns the current config of the fake bridge.
+func (s *Server) Config() hue.Config {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.config
+}
+
+// SetConfig replaces the config of the fake bridge.
+func (s *Server) SetConfig(config hue.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.config = config
+}
+
+// AddLight adds a light to the fake bridge, returning the ID it was assigned.
+// The light is reachable unless SetUnreachable is called.
+func (s *Server) AddLight(light hue.Light) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	light.ID = s.allocateID("lights")
+	light.State.Reachable = true
+	s.lights[light.ID] = &light
+
+	return light.ID
+}
+
+// Light returns the current state of the specified light.
+func (s *Server) Light(id string) (hue.Light, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	light, ok := s.lights[id]
+	if !ok {
+		return hue.Light{}, false
+	}
+
+	return *light, true
+}
+
+// SetUnreachable changes whether the specified light can be reached by the fake bridge.
+// As with a real bridge, state changes to an unreachable light are reported as successful but are not applied.
+func (s *Server) SetUnreachable(lightID string, unreachable bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unreachable[lightID] = unreachable
+	if light, ok := s.lights[lightID]; ok {
+		light.State.Reachable = !unreachable
+	}
+}
+
+// AddSensor adds a sensor to the fake bridge, returning the ID it was assigned.
+func (s *Server) AddSensor(sensor hue.Sensor) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sensor.ID = s.allocateID("sensors")
+	s.sensors[sensor.ID] = &sensor
+
+	return sensor.ID
+}
+
+// Sensor returns the current state of the specified sensor.
+func (s *Server) Sensor(id string) (hue.Sensor, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sensor, ok := s.sensors[id]
+	if !ok {
+		return hue.Sensor{}, false
+	}
+
+	return *sensor, true
+}
+
+// AddGroup adds a group to the fake bridge, returning the ID it was assigned.
+func (s *Server) AddGroup(group hue.Group) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group.ID = s.allocateID("groups")
+	s.stored["groups"][group.ID] = &group
+
+	return group.ID
+}
+
+// Group returns the current state of the specified group.
+func (s *Server) Group(id string) (hue.Group, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group, ok := s.stored["groups"][id].(*hue.Group)
+	if !ok {
+		return hue.Group{}, false
+	}
+
+	return *group, true
+}
+
+// AddScene adds a scene to the fake bridge, returning the ID it was assigned.
+func (s *Server) AddScene(scene hue.Scene) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scene.ID = s.allocateID("scenes")
+	s.stored["scenes"][scene.ID] = &scene
+
+	return scene.ID
+}
+
+// Scene returns the current state of the specified scene.
+func (s *Server) Scene(id string) (hue.Scene, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scene, ok := s.stored["scenes"][id].(*hue.Scene)
+	if !ok {
+		return hue.Scene{}, false
+	}
+
+	return *scene, true
+}
+
+// AddRule adds a rule to the fake bridge, returning the ID it was assigned.
+func (s *Server) AddRule(rule hue.Rule) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rule.ID = s.allocateID("rules")
+	s.stored["rules"][rule.ID] = &rule
+
+	return rule.ID
+}
+
+// Rule returns the current state of the specified rule.
+func (s *Server) Rule(id string) (hue.Rule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rule, ok := s.stored["rules"][id].(*hue.Rule)
+	if !ok {
+		return hue.Rule{}, false
+	}
+
+	return *rule, true
+}
+
+// InjectError causes every request with the specified method and path to fail with an error of the specified type.
+// The path is relative to the username, such as /lights/1/state. An error type of 0 removes the injected error.
+func (s *Server) InjectError(method string, path string, errorType int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := method + " " + path
+	if errorType == 0 {
+		delete(s.injected, key)
+		return
+	}
+
+	s.injected[key] = hue.ResponseError{
+		Type:        errorType,
+		Address:     path,
+		Description: "injected error " + strconv.Itoa(errorType),
+	}
+}
+
+// SetLatency delays every response from the fake bridge by the specified duration.
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.latency = latency
+}
+
+// Requests returns the API requests received by the fake bridge, in the order they were received.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// ResetRequests clears the record of the API requests received.
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+}
+
+// AssertRequested fails the test unless a request with the specified method and path was received.
+// The matching request is returned so its body can be inspected.
+func (s *Server) AssertRequested(t testing.TB, method string, path string) Request {
+	t.Helper()
+
+	for _, req := range s.Requests() {
+		if req.Method == method && req.Path == path {
+			return req
+		}
+	}
+
+	t.Errorf("Expected a %s request to %s\n", method, path)
+	return Request{}
+}
+
+// AssertNotRequested fails the test if a request with the specified method and path was received.
+func (s *Server) AssertNotRequested(t testing.TB, method string, path string) {
+	t.Helper()
+
+	for _, req := range s.Requests() {
+		if req.Method == method && req.Path == path {
+			t.Errorf("Expected no %s request to %s\n", method, path)
+			return
+		}
+	}
+}
+
+// AssertRequestCount fails the test unless the specified number of API requests were received.
+func (s *Server) AssertRequestCount(t testing.TB, count int) {
+	t.Helper()
+
+	if reqs := s.Requests(); len(reqs) != count {
+		t.Errorf("Expected %d requests, got %d\n", count, len(reqs))
+	}
+}
+
+// allocateID returns the next unused ID for the specified kind of resource; the caller must hold the lock.
+func (s *Server) allocateID(kind string) string {
+	s.nextIDs[kind]++
+	return strconv.Itoa(s.nextIDs[kind])
+}
@@ -0,0 +1,292 @@
+package huetest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	hue "github.com/rmrobinson/hue-go"
+)
+
+func TestServer_Lights(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	id := s.AddLight(hue.Light{Name: "Hue lamp 1", Model: "Extended color light", ModelID: "LCT001"})
+
+	b, err := s.Bridge()
+	if err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	lights, err := b.Lights()
+	if err != nil {
+		t.Fatalf("Unable to retrieve lights: %s\n", err.Error())
+	} else if len(lights) != 1 || lights[0].ID != id || lights[0].Name != "Hue lamp 1" {
+		t.Fatalf("Unexpected lights returned: %+v\n", lights)
+	}
+
+	var args hue.LightStateArg
+	args.SetBrightness(100)
+
+	if err = b.SetLightState(id, &args); !errors.Is(err, hue.ErrDeviceOff) {
+		t.Errorf("Expected device off error, got %v\n", err)
+	}
+
+	args.Reset()
+	args.SetIsOn(true)
+	args.SetBrightness(100)
+
+	if err = b.SetLightState(id, &args); err != nil {
+		t.Fatalf("Unable to set light state: %s\n", err.Error())
+	}
+
+	if light, _ := s.Light(id); !light.State.On || light.State.Brightness != 100 {
+		t.Errorf("Expected light to be on at brightness 100, got %+v\n", light.State)
+	}
+
+	req := s.AssertRequested(t, "PUT", "/lights/"+id+"/state")
+	if len(req.Body) < 1 {
+		t.Errorf("Expected state change body to be recorded\n")
+	}
+}
+
+func TestServer_Unreachable(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	id := s.AddLight(hue.Light{Name: "Hue lamp 1"})
+	s.SetUnreachable(id, true)
+
+	b, err := s.Bridge()
+	if err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	var args hue.LightStateArg
+	args.SetIsOn(true)
+
+	if err = b.SetLightState(id, &args); err != nil {
+		t.Fatalf("Unable to set light state: %s\n", err.Error())
+	}
+
+	light, err := b.Light(id)
+	if err != nil {
+		t.Fatalf("Unable to retrieve light: %s\n", err.Error())
+	} else if light.State.On || light.State.Reachable {
+		t.Errorf("Expected unreachable light to be unchanged, got %+v\n", light.State)
+	}
+}
+
+func TestServer_Increments(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	id := s.AddLight(hue.Light{Name: "Hue lamp 1", State: hue.LightState{On: true, Brightness: 100, Hue: 65000}})
+
+	b, err := s.Bridge()
+	if err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	var args hue.LightStateArg
+	args.SetBrightnessIncrement(-30)
+	args.SetHueIncrement(1000)
+
+	if err = b.SetLightState(id, &args); err != nil {
+		t.Fatalf("Unable to set light state: %s\n", err.Error())
+	}
+
+	if args.Brightness() != 70 || args.Hue() != 464 {
+		t.Errorf("Expected the resulting brightness and hue to be reported, got %d and %d\n", args.Brightness(), args.Hue())
+	}
+
+	light, err := b.Light(id)
+	if err != nil {
+		t.Fatalf("Unable to retrieve light: %s\n", err.Error())
+	} else if light.State.Brightness != 70 {
+		t.Errorf("Expected the light to be dimmed to 70, got %d\n", light.State.Brightness)
+	}
+}
+
+func TestServer_IncrementsReused(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	id := s.AddLight(hue.Light{Name: "Hue lamp 1", State: hue.LightState{On: true, Brightness: 100}})
+
+	b, err := s.Bridge()
+	if err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	// A dimmer button reuses the same state for every press.
+	var args hue.LightStateArg
+	args.SetBrightnessIncrement(-10)
+
+	for _, expected := range []uint8{90, 80, 70} {
+		if err = b.SetLightState(id, &args); err != nil {
+			t.Fatalf("Unable to set light state: %s\n", err.Error())
+		}
+
+		if args.Brightness() != expected {
+			t.Errorf("Expected the resulting brightness to be %d, got %d\n", expected, args.Brightness())
+		}
+
+		light, err := b.Light(id)
+		if err != nil {
+			t.Fatalf("Unable to retrieve light: %s\n", err.Error())
+		} else if light.State.Brightness != expected {
+			t.Errorf("Expected the light to be dimmed to %d, got %d\n", expected, light.State.Brightness)
+		}
+	}
+}
+
+func TestServer_InjectError(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	b, err := s.Bridge()
+	if err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	s.InjectError("PUT", "/config", 901)
+
+	var args hue.ConfigArg
+	args.SetName("Test")
+
+	if err = b.SetConfig(&args); !errors.Is(err, hue.ErrInternal) {
+		t.Errorf("Expected internal error, got %v\n", err)
+	}
+
+	s.InjectError("PUT", "/config", 0)
+
+	if err = b.SetConfig(&args); err != nil {
+		t.Errorf("Unable to set config: %s\n", err.Error())
+	} else if s.Config().Name != "Test" {
+		t.Errorf("Expected config name to be updated, got %s\n", s.Config().Name)
+	}
+}
+
+func TestServer_Latency(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	b, err := s.Bridge()
+	if err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	s.SetLatency(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err = b.LightsWithContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline to be exceeded, got %v\n", err)
+	}
+}
+
+func TestServer_Pair(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	b := hue.NewBridge("")
+	if err := b.InitIP(s.srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if err := b.Pair("huetest", "device"); !errors.Is(err, hue.ErrLinkButtonNotPressed) {
+		t.Errorf("Expected link button not pressed, got %v\n", err)
+	}
+
+	s.PressLinkButton()
+
+	if err := b.Pair("huetest", "device"); err != nil {
+		t.Fatalf("Unable to pair: %s\n", err.Error())
+	} else if b.Username != DefaultUsername {
+		t.Errorf("Expected username %s, got %s\n", DefaultUsername, b.Username)
+	}
+}
+
+func TestServer_DeleteLightAndReferences(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+
+	id := s.AddLight(hue.Light{Name: "Hue lamp 1"})
+	other := s.AddLight(hue.Light{Name: "Hue lamp 2"})
+
+	room := s.AddGroup(hue.Group{Name: "Room", Type: hue.GroupTypeRoom, Lights: []string{id}})
+	zone := s.AddGroup(hue.Group{Name: "Zone", Type: hue.GroupTypeZone, Lights: []string{id}})
+	lightGroup := s.AddGroup(hue.Group{Name: "Group", Type: "LightGroup", Lights: []string{id}})
+	shared := s.AddGroup(hue.Group{Name: "Shared", Type: "LightGroup", Lights: []string{id, other}})
+
+	groupScene := s.AddScene(hue.Scene{Name: "Group scene", Type: hue.SceneTypeGroup, Group: shared, Lights: []string{id, other}})
+	lightScene := s.AddScene(hue.Scene{Name: "Light scene", Type: "LightScene", Lights: []string{id, other}})
+	emptyScene := s.AddScene(hue.Scene{Name: "Empty scene", Type: "LightScene", Lights: []string{id}})
+
+	otherAction := hue.RuleAction{Address: "/lights/" + other + "/state", Method: "PUT", Body: map[string]interface{}{"on": true}}
+	lightAction := hue.RuleAction{Address: "/lights/" + id + "/state", Method: "PUT", Body: map[string]interface{}{"on": true}}
+
+	conditionRule := s.AddRule(hue.Rule{
+		Name:       "Condition",
+		Conditions: []hue.RuleCondition{{Address: "/lights/" + id + "/state/on", Operator: "eq", Value: "true"}},
+		Actions:    []hue.RuleAction{otherAction},
+	})
+	actionRule := s.AddRule(hue.Rule{Name: "Action", Actions: []hue.RuleAction{lightAction}})
+	sharedRule := s.AddRule(hue.Rule{Name: "Shared", Actions: []hue.RuleAction{lightAction, otherAction}})
+	unrelatedRule := s.AddRule(hue.Rule{Name: "Unrelated", Actions: []hue.RuleAction{otherAction}})
+	noActionsRule := s.AddRule(hue.Rule{
+		Name:       "No actions",
+		Conditions: []hue.RuleCondition{{Address: "/lights/" + other + "/state/on", Operator: "eq", Value: "true"}},
+	})
+
+	b, err := s.Bridge()
+	if err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if err = b.DeleteLightAndReferences(id); err != nil {
+		t.Fatalf("Unable to delete light: %s\n", err.Error())
+	}
+
+	if _, ok := s.Light(id); ok {
+		t.Errorf("Expected the light to be deleted\n")
+	}
+
+	for _, groupID := range []string{room, zone} {
+		if group, ok := s.Group(groupID); !ok || len(group.Lights) != 0 {
+			t.Errorf("Expected the empty %s to be kept without the light, got %+v\n", group.Type, group)
+		}
+	}
+	if _, ok := s.Group(lightGroup); ok {
+		t.Errorf("Expected the empty group to be deleted\n")
+	}
+	if group, _ := s.Group(shared); len(group.Lights) != 1 || group.Lights[0] != other {
+		t.Errorf("Expected the light to be removed from the shared group, got %v\n", group.Lights)
+	}
+
+	s.AssertNotRequested(t, "PUT", "/scenes/"+groupScene)
+	if scene, _ := s.Scene(lightScene); len(scene.Lights) != 1 || scene.Lights[0] != other {
+		t.Errorf("Expected the light to be removed from the scene, got %v\n", scene.Lights)
+	}
+	if _, ok := s.Scene(emptyScene); ok {
+		t.Errorf("Expected the empty scene to be deleted\n")
+	}
+
+	if _, ok := s.Rule(conditionRule); ok {
+		t.Errorf("Expected the rule with a condition on the light to be deleted\n")
+	}
+	if _, ok := s.Rule(actionRule); ok {
+		t.Errorf("Expected the rule left without actions to be deleted\n")
+	}
+	if rule, _ := s.Rule(sharedRule); len(rule.Actions) != 1 || rule.Actions[0].Address != otherAction.Address {
+		t.Errorf("Expected the action on the light to be removed, got %+v\n", rule.Actions)
+	}
+	s.AssertNotRequested(t, "PUT", "/rules/"+unrelatedRule)
+	if _, ok := s.Rule(noActionsRule); !ok {
+		t.Errorf("Expected the rule without actions which didn't reference the light to be kept\n")
+	}
+}
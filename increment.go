@@ -0,0 +1,119 @@
+package hue
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	maxBrightnessIncrement        = 254
+	maxSaturationIncrement        = 254
+	maxHueIncrement               = 65534
+	maxColourTemperatureIncrement = 65534
+	maxXYIncrement                = 0.5
+)
+
+// incrementLimits are the largest changes the bridge accepts for each increment, keyed by the increment name.
+var incrementLimits = map[string]int64{
+	"bri_inc": maxBrightnessIncrement,
+	"sat_inc": maxSaturationIncrement,
+	"hue_inc": maxHueIncrement,
+	"ct_inc":  maxColourTemperatureIncrement,
+}
+
+// incrementResults hold a value of the type of each absolute value which can be changed by an increment.
+// The bridge reports the resulting absolute value rather than the increment, which is decoded into this type.
+var incrementResults = map[string]interface{}{
+	"bri": uint8(0),
+	"sat": uint8(0),
+	"hue": uint16(0),
+	"ct":  uint16(0),
+	"xy":  [2]float64{},
+}
+
+// clampIncrement limits the supplied increment to +/-max.
+func clampIncrement(inc int64, max int64) int64 {
+	if inc > max {
+		return max
+	} else if inc < -max {
+		return -max
+	}
+	return inc
+}
+
+// clampXYIncrement limits the supplied XY increment to the range accepted by the bridge.
+func clampXYIncrement(inc float64) float64 {
+	if inc > maxXYIncrement {
+		return maxXYIncrement
+	} else if inc < -maxXYIncrement {
+		return -maxXYIncrement
+	}
+	return inc
+}
+
+// isIncrement returns whether the specified key is a change relative to the current value.
+func isIncrement(key string) bool {
+	return strings.HasSuffix(key, "_inc")
+}
+
+// addIncrements combines two increments of the same key, such as two queued changes which are being merged.
+func addIncrements(key string, a interface{}, b interface{}) interface{} {
+	switch av := a.(type) {
+	case int16:
+		if bv, ok := b.(int16); ok {
+			return int16(clampIncrement(int64(av)+int64(bv), incrementLimits[key]))
+		}
+	case int32:
+		if bv, ok := b.(int32); ok {
+			return int32(clampIncrement(int64(av)+int64(bv), incrementLimits[key]))
+		}
+	case [2]float64:
+		if bv, ok := b.([2]float64); ok {
+			return [2]float64{clampXYIncrement(av[0] + bv[0]), clampXYIncrement(av[1] + bv[1])}
+		}
+	}
+
+	return b
+}
+
+// applyIncrement returns the absolute value of the specified key once the increment has been applied to it, limited to
+// the range of the value as the bridge does.
+func applyIncrement(key string, absolute interface{}, inc interface{}) interface{} {
+	switch av := absolute.(type) {
+	case uint8:
+		if iv, ok := inc.(int16); ok {
+			min := int64(0)
+			if key == "bri" {
+				min = 1
+			}
+			return uint8(clampRange(int64(av)+int64(iv), min, 254))
+		}
+	case uint16:
+		if iv, ok := inc.(int32); ok {
+			if key == "hue" {
+				// Hue wraps around, as it is an angle.
+				return uint16(((int64(av)+int64(iv))%65536 + 65536) % 65536)
+			}
+			return uint16(clampRange(int64(av)+int64(iv), 153, 500))
+		}
+	case [2]float64:
+		if iv, ok := inc.([2]float64); ok {
+			return [2]float64{
+				math.Min(math.Max(av[0]+iv[0], 0), 1),
+				math.Min(math.Max(av[1]+iv[1], 0), 1),
+			}
+		}
+	}
+
+	return absolute
+}
+
+// clampRange limits the supplied value to between min and max inclusive.
+func clampRange(v int64, min int64, max int64) int64 {
+	if v < min {
+		return min
+	} else if v > max {
+		return max
+	}
+	return v
+}
@@ -0,0 +1,55 @@
+package hue
+
+import "testing"
+
+func TestLightStateArg_Increments(t *testing.T) {
+	var args LightStateArg
+	args.SetBrightnessIncrement(-300)
+	args.SetHueIncrement(70000)
+	args.SetXYIncrement(XY{X: 0.75, Y: -0.1})
+
+	if args.BrightnessIncrement() != -254 {
+		t.Errorf("Expected the brightness increment to be limited to -254, got %d\n", args.BrightnessIncrement())
+	}
+	if args.HueIncrement() != 65534 {
+		t.Errorf("Expected the hue increment to be limited to 65534, got %d\n", args.HueIncrement())
+	}
+	if xy := args.XYIncrement(); xy.X != 0.5 || xy.Y != -0.1 {
+		t.Errorf("Expected the XY increment to be limited to 0.5, got %v\n", xy)
+	}
+}
+
+func TestAddIncrements(t *testing.T) {
+	if v := addIncrements("bri_inc", int16(200), int16(100)); v != int16(254) {
+		t.Errorf("Expected merged brightness increments to be limited to 254, got %v\n", v)
+	}
+	if v := addIncrements("ct_inc", int32(-20), int32(5)); v != int32(-15) {
+		t.Errorf("Expected merged colour temperature increments to be added, got %v\n", v)
+	}
+	if v := addIncrements("xy_inc", [2]float64{0.1, 0.4}, [2]float64{0.1, 0.4}); v != [2]float64{0.2, 0.5} {
+		t.Errorf("Expected merged XY increments to be added and limited, got %v\n", v)
+	}
+}
+
+func TestMergeValues(t *testing.T) {
+	pending := map[string]interface{}{"bri": uint8(100), "hue": uint16(65000)}
+	mergeValues(pending, map[string]interface{}{"bri_inc": int16(-120), "hue_inc": int32(1000), "sat_inc": int16(10)})
+
+	if _, ok := pending["bri_inc"]; ok {
+		t.Errorf("Expected the brightness increment to be applied to the queued brightness, got %v\n", pending)
+	}
+	if pending["bri"] != uint8(1) {
+		t.Errorf("Expected the queued brightness to be limited to 1, got %v\n", pending["bri"])
+	}
+	if pending["hue"] != uint16(464) {
+		t.Errorf("Expected the queued hue to wrap around to 464, got %v\n", pending["hue"])
+	}
+	if pending["sat_inc"] != int16(10) {
+		t.Errorf("Expected the saturation increment to be queued, got %v\n", pending["sat_inc"])
+	}
+
+	mergeValues(pending, map[string]interface{}{"sat": uint8(50)})
+	if _, ok := pending["sat_inc"]; ok || pending["sat"] != uint8(50) {
+		t.Errorf("Expected the saturation to replace the queued increment, got %v\n", pending)
+	}
+}
@@ -0,0 +1,225 @@
+// Package bridgeapi contains the parts of the v1 REST API which are shared by the fake bridges in huetest and emulator.
+package bridgeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	hue "github.com/rmrobinson/hue-go"
+)
+
+const descriptionTemplate = `<?xml version="1.0" encoding="UTF-8" ?>
+<root xmlns="urn:schemas-upnp-org:device-1-0">
+<specVersion>
+<major>1</major>
+<minor>0</minor>
+</specVersion>
+<URLBase>%s/</URLBase>
+<device>
+<deviceType>urn:schemas-upnp-org:device:Basic:1</deviceType>
+<friendlyName>%s</friendlyName>
+<manufacturer>Royal Philips Electronics</manufacturer>
+<manufacturerURL>http://www.philips.com</manufacturerURL>
+<modelDescription>Philips hue Personal Wireless Lighting</modelDescription>
+<modelName>Philips hue bridge 2015</modelName>
+<modelNumber>BSB002</modelNumber>
+<modelURL>http://www.meethue.com</modelURL>
+<serialNumber>%s</serialNumber>
+<UDN>uuid:%s</UDN>
+<presentationURL>index.html</presentationURL>
+</device>
+</root>`
+
+// LightStateKeys are the light state values which applications can change.
+var LightStateKeys = map[string]bool{
+	"on":     true,
+	"bri":    true,
+	"hue":    true,
+	"sat":    true,
+	"xy":     true,
+	"ct":     true,
+	"alert":  true,
+	"effect": true,
+}
+
+// EmptyCollections returns the resource collections which a fake bridge reports, but does not store anything in,
+// along with any other kinds specified.
+func EmptyCollections(kinds ...string) map[string]bool {
+	collections := map[string]bool{
+		"groups":        true,
+		"scenes":        true,
+		"schedules":     true,
+		"rules":         true,
+		"resourcelinks": true,
+	}
+
+	for _, kind := range kinds {
+		collections[kind] = true
+	}
+
+	return collections
+}
+
+// WriteDescription writes the UPnP description of a bridge served from the supplied base URL.
+func WriteDescription(w http.ResponseWriter, baseURL string, name string, serialNumber string, udn string) {
+	w.Header().Set("Content-Type", "text/xml")
+	fmt.Fprintf(w, descriptionTemplate, baseURL, name, serialNumber, udn)
+}
+
+// ApplyIncrements replaces any increments in the requested values with the absolute values they result in, which is
+// what the bridge reports. As on a real bridge, an increment is ignored if the absolute value is also requested.
+func ApplyIncrements(state hue.LightState, values map[string]json.RawMessage) {
+	for _, key := range SortedKeys(values) {
+		if !strings.HasSuffix(key, "_inc") {
+			continue
+		}
+
+		name := strings.TrimSuffix(key, "_inc")
+		if _, ok := values[name]; ok {
+			delete(values, key)
+			continue
+		}
+
+		var result interface{}
+		var inc int
+		var xyInc [2]float64
+
+		if name == "xy" {
+			if err := json.Unmarshal(values[key], &xyInc); err != nil {
+				continue
+			}
+		} else if err := json.Unmarshal(values[key], &inc); err != nil {
+			continue
+		}
+
+		switch name {
+		case "bri":
+			result = clamp(int(state.Brightness)+inc, 1, 254)
+		case "sat":
+			result = clamp(int(state.Saturation)+inc, 0, 254)
+		case "hue":
+			// Hue wraps around, as it is an angle.
+			result = ((int(state.Hue)+inc)%65536 + 65536) % 65536
+		case "ct":
+			result = clamp(int(state.ColorTemperature)+inc, 153, 500)
+		case "xy":
+			result = [2]float64{
+				math.Min(math.Max(state.XY[0]+xyInc[0], 0), 1),
+				math.Min(math.Max(state.XY[1]+xyInc[1], 0), 1),
+			}
+		default:
+			continue
+		}
+
+		delete(values, key)
+		values[name], _ = json.Marshal(result)
+	}
+}
+
+// clamp limits the supplied value to the specified range.
+func clamp(v int, min int, max int) int {
+	if v < min {
+		return min
+	} else if v > max {
+		return max
+	}
+	return v
+}
+
+// ApplyValues sets each of the supplied values on the resource and returns the response entries, along with the keys
+// which were applied. Values which the resource doesn't have, or which aren't in the allowed keys if specified, are rejected.
+func ApplyValues(resource interface{}, values map[string]json.RawMessage, path string, allowed map[string]bool) ([]interface{}, []string) {
+	current, err := json.Marshal(resource)
+	if err != nil {
+		return ErrorEntries(901, path, err.Error()), nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(current, &fields); err != nil {
+		return ErrorEntries(901, path, err.Error()), nil
+	}
+
+	var entries []interface{}
+	var applied []string
+
+	for _, key := range SortedKeys(values) {
+		address := path + "/" + key
+
+		old, ok := fields[key]
+		if !ok || (allowed != nil && !allowed[key]) {
+			entries = append(entries, ErrorEntry(6, address, "parameter, "+key+", not available"))
+			continue
+		}
+
+		fields[key] = values[key]
+		updated, _ := json.Marshal(fields)
+
+		if err = json.Unmarshal(updated, resource); err != nil {
+			// Restore the previous value, as a failed decode may have partially updated the resource.
+			fields[key] = old
+			restored, _ := json.Marshal(fields)
+			json.Unmarshal(restored, resource)
+
+			entries = append(entries, ErrorEntry(7, address, "invalid value, "+string(values[key])+", for parameter, "+key))
+			continue
+		}
+
+		entries = append(entries, SuccessEntry(address, values[key]))
+		applied = append(applied, key)
+	}
+
+	return entries, applied
+}
+
+// SuccessEntry returns a response entry reporting the value applied at the specified address.
+func SuccessEntry(address string, value interface{}) interface{} {
+	return map[string]interface{}{
+		"success": map[string]interface{}{address: value},
+	}
+}
+
+// SuccessEntries returns a response containing a single success entry; the value is reported on its own if there is no address.
+func SuccessEntries(address string, value interface{}) []interface{} {
+	if len(address) < 1 {
+		return []interface{}{map[string]interface{}{"success": value}}
+	}
+
+	return []interface{}{SuccessEntry(address, value)}
+}
+
+// ErrorEntry returns a response entry reporting an error at the specified address.
+func ErrorEntry(errorType int, address string, description string) interface{} {
+	return map[string]interface{}{
+		"error": hue.ResponseError{
+			Type:        errorType,
+			Address:     address,
+			Description: description,
+		},
+	}
+}
+
+// ErrorEntries returns a response containing a single error entry.
+func ErrorEntries(errorType int, address string, description string) []interface{} {
+	return []interface{}{ErrorEntry(errorType, address, description)}
+}
+
+// SortedKeys returns the keys of the supplied values in order, so responses are deterministic.
+func SortedKeys(values map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// WriteJSON writes the supplied value as the JSON response.
+func WriteJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
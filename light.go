@@ -1,7 +1,7 @@
 package hue
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -36,53 +36,104 @@ type Light struct {
 	State LightState `json:"state"`
 }
 
+// deriveRGB populates the RGB value of the light state from whichever colour mode the light is currently in.
+func (l *Light) deriveRGB() {
+	if l.State.ColorMode == "xy" {
+		xy := XY{X: l.State.XY[0], Y: l.State.XY[1]}
+		l.State.RGB.FromXY(xy, l.ModelID)
+	} else if l.State.ColorMode == "ct" {
+		l.State.RGB.FromCT(l.State.ColorTemperature)
+	} else if l.State.ColorMode == "hs" {
+		hsb := HSB{Hue: l.State.Hue, Saturation: l.State.Saturation, Brightness: l.State.Brightness}
+		l.State.RGB.FromHSB(hsb)
+	}
+}
+
 // NewLight represents a single, un-configured light.
 type NewLight struct {
 	ID   string
 	Name string `json:"name"`
 }
 
+// maxSearchDeviceIDs is the largest number of device IDs the bridge accepts in a single search.
+const maxSearchDeviceIDs = 10
+
+// ErrTooManyDeviceIDs is returned if more device IDs are supplied to a search than the bridge supports.
+var ErrTooManyDeviceIDs = errors.New("too many device IDs supplied")
+
 // SearchForNewLights begins the process of locating new lights on the bridge.
-func (b *Bridge) SearchForNewLights() error {
-	return errors.New("search is not yet supported")
+// The search runs for roughly a minute; NewLights reports the results and whether the search is still active.
+// Up to 10 Zigbee device IDs (the serial printed on the light) may be supplied to locate lights which were already paired with another bridge.
+func (b *Bridge) SearchForNewLights(deviceIDs ...string) error {
+	return b.SearchForNewLightsWithContext(context.Background(), deviceIDs...)
 }
 
-// NewLights returns the collection of newly discovered lights.
-// Only returns a value if SearchForNewLights has previously been called.
-func (b *Bridge) NewLights() ([]NewLight, error) {
+// SearchForNewLightsWithContext is like SearchForNewLights, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SearchForNewLightsWithContext(ctx context.Context, deviceIDs ...string) error {
 	if !b.isAvailable() {
-		return nil, ErrBridgeNotAvailable
-	} else if b.updateInProgress {
-		return nil, ErrBridgeUpdating
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	} else if len(deviceIDs) > maxSearchDeviceIDs {
+		return ErrTooManyDeviceIDs
+	}
+
+	reqBody := struct {
+		DeviceIDs []string `json:"deviceid,omitempty"`
+	}{
+		DeviceIDs: deviceIDs,
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/lights/new"
+	return b.do(ctx, http.MethodPost, "/lights", reqBody)
+}
+
+// NewLights returns the collection of lights discovered by the most recent search, along with the status of that search.
+// Only returns lights if SearchForNewLights has previously been called.
+func (b *Bridge) NewLights() ([]NewLight, ScanStatus, error) {
+	return b.NewLightsWithContext(context.Background())
+}
 
-	resp, err := http.Get(url)
+// NewLightsWithContext is like NewLights, but the request is cancelled once the supplied context is done.
+func (b *Bridge) NewLightsWithContext(ctx context.Context) ([]NewLight, ScanStatus, error) {
+	var status ScanStatus
+
+	if !b.isAvailable() {
+		return nil, status, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return nil, status, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/lights/new"
+
+	resp, err := b.get(ctx, url)
 	if err != nil {
-		return nil, err
+		return nil, status, err
 	}
 
+	defer closeBody(resp.Body)
+
 	var respEntries map[string]*json.RawMessage
 
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
+	err = decodeResponse(resp.Body, &respEntries)
 	if err != nil {
-		return nil, err
+		return nil, status, err
 	}
 
 	var lights []NewLight
 	for key, respEntry := range respEntries {
 		if key == "lastscan" {
-			var time string
-			if err = json.Unmarshal(*respEntry, &time); err != nil {
-				return nil, err
+			var lastScan string
+			if err = json.Unmarshal(*respEntry, &lastScan); err != nil {
+				return nil, status, err
 			}
 
-			// TODO: do something with this value?
+			if status, err = parseScanStatus(lastScan); err != nil {
+				return nil, status, err
+			}
 		} else {
 			var l NewLight
 			if err = json.Unmarshal(*respEntry, &l); err != nil {
-				return nil, err
+				return nil, status, err
 			}
 
 			l.ID = key
@@ -91,27 +142,34 @@ func (b *Bridge) NewLights() ([]NewLight, error) {
 		}
 	}
 
-	return lights, nil
+	return lights, status, nil
 }
 
 // Lights returns the collection of lights configured on the bridge.
 func (b *Bridge) Lights() ([]Light, error) {
+	return b.LightsWithContext(context.Background())
+}
+
+// LightsWithContext is like Lights, but the request is cancelled once the supplied context is done.
+func (b *Bridge) LightsWithContext(ctx context.Context) ([]Light, error) {
 	if !b.isAvailable() {
 		return nil, ErrBridgeNotAvailable
-	} else if b.updateInProgress {
+	} else if b.IsUpdating() {
 		return nil, ErrBridgeUpdating
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/lights"
+	url := b.apiURL() + "/lights"
 
-	res, err := http.Get(url)
+	res, err := b.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
 
+	defer closeBody(res.Body)
+
 	var respBody map[string]Light
 
-	err = json.NewDecoder(res.Body).Decode(&respBody)
+	err = decodeResponse(res.Body, &respBody)
 	if err != nil {
 		return nil, err
 	}
@@ -120,15 +178,7 @@ func (b *Bridge) Lights() ([]Light, error) {
 	for id, light := range respBody {
 		light.ID = id
 
-		if light.State.ColorMode == "xy" {
-			xy := XY{X: light.State.XY[0], Y: light.State.XY[1]}
-			light.State.RGB.FromXY(xy, light.ModelID)
-		} else if light.State.ColorMode == "ct" {
-			light.State.RGB.FromCT(light.State.ColorTemperature)
-		} else if light.State.ColorMode == "hs" {
-			hsb := HSB{Hue: light.State.Hue, Saturation: light.State.Saturation, Brightness: light.State.Brightness}
-			light.State.RGB.FromHSB(hsb)
-		}
+		light.deriveRGB()
 
 		lights = append(lights, light)
 	}
@@ -138,201 +188,224 @@ func (b *Bridge) Lights() ([]Light, error) {
 
 // Light returns a single light from the bridge.
 func (b *Bridge) Light(id string) (Light, error) {
+	return b.LightWithContext(context.Background(), id)
+}
+
+// LightWithContext is like Light, but the request is cancelled once the supplied context is done.
+func (b *Bridge) LightWithContext(ctx context.Context, id string) (Light, error) {
 	if !b.isAvailable() {
 		return Light{}, ErrBridgeNotAvailable
-	} else if b.updateInProgress {
+	} else if b.IsUpdating() {
 		return Light{}, ErrBridgeUpdating
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/lights/" + id
+	url := b.apiURL() + "/lights/" + id
 
-	resp, err := http.Get(url)
+	resp, err := b.get(ctx, url)
 	if err != nil {
 		return Light{}, err
 	}
 
+	defer closeBody(resp.Body)
+
 	var light Light
-	err = json.NewDecoder(resp.Body).Decode(&light)
+	err = decodeResponse(resp.Body, &light)
 	if err != nil {
 		return light, err
 	}
 
-	if light.State.ColorMode == "xy" {
-		xy := XY{X: light.State.XY[0], Y: light.State.XY[1]}
-		light.State.RGB.FromXY(xy, light.ModelID)
-	} else if light.State.ColorMode == "ct" {
-		light.State.RGB.FromCT(light.State.ColorTemperature)
-	} else if light.State.ColorMode == "hs" {
-		hsb := HSB{Hue: light.State.Hue, Saturation: light.State.Saturation, Brightness: light.State.Brightness}
-		light.State.RGB.FromHSB(hsb)
-	}
+	light.deriveRGB()
 
 	return light, nil
 }
 
 // SetLight updates the specified light with the configuration supplied.
 func (b *Bridge) SetLight(id string, args *LightArg) error {
+	return b.SetLightWithContext(context.Background(), id, args)
+}
+
+// SetLightWithContext is like SetLight, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetLightWithContext(ctx context.Context, id string, args *LightArg) error {
 	if !b.isAvailable() {
 		return ErrBridgeNotAvailable
-	} else if b.updateInProgress {
+	} else if b.IsUpdating() {
 		return ErrBridgeUpdating
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/lights/" + id
+	if err := b.validate(args); err != nil {
+		return err
+	}
 
-	buf := new(bytes.Buffer)
+	return b.update(ctx, "/lights/"+id, (*arg)(args))
+}
 
-	err := json.NewEncoder(buf).Encode(args.args)
-	if err != nil {
-		return err
+// SetLightState sets the specified light with the supplied light state.
+func (b *Bridge) SetLightState(id string, args *LightStateArg) error {
+	return b.SetLightStateWithContext(context.Background(), id, args)
+}
+
+// SetLightStateWithContext is like SetLightState, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetLightStateWithContext(ctx context.Context, id string, args *LightStateArg) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
 	}
 
-	req, err := http.NewRequest(http.MethodPut, url, buf)
-	if err != nil {
+	if err := b.validate(args); err != nil {
 		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
+	path := "/lights/" + id + "/state"
+	url := b.apiURL() + path
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+	var respEntries responseEntries
+	var err error
+
+	if b.lightQueue != nil {
+		respEntries, err = b.lightQueue.submit(ctx, url, args.args)
+	} else {
+		respEntries, err = b.putState(ctx, url, args.args)
 	}
 
-	var respEntries responseEntries
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
 	if err != nil {
 		return err
 	}
 
-	for _, respEntry := range respEntries {
-		var e responseEntry
-		if err = json.Unmarshal(respEntry, &e); err != nil {
-			return err
-		}
-
-		if e.Error.Type > 0 {
-			if args.errors == nil {
-				args.errors = make(map[string]ResponseError)
-			}
-
-			keys := strings.Split(e.Error.Address, "/")
-			key := keys[len(keys)-1]
-
-			args.errors[key] = e.Error
-		} else {
-			for path, jsonValue := range e.Success {
-				keys := strings.Split(path, "/")
+	return (*arg)(args).applyResponse(path, respEntries)
+}
 
-				key := keys[len(keys)-1]
+// putState sends the supplied light state values to the specified URL, returning the response entries.
+// This is shared by every endpoint which accepts a light state, and is how queued commands are sent.
+func (b *Bridge) putState(ctx context.Context, url string, values map[string]interface{}) (responseEntries, error) {
+	return b.send(ctx, http.MethodPut, url, values)
+}
 
-				if key == "name" {
-					var v string
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
+// DeleteLight removes the specified light from the bridge.
+// Any groups, scenes and rules which reference the light are left as-is; use DeleteLightAndReferences to remove them too.
+func (b *Bridge) DeleteLight(id string) error {
+	return b.DeleteLightWithContext(context.Background(), id)
+}
 
-					args.args[key] = v
-				}
-			}
-		}
-	}
+// DeleteLightWithContext is like DeleteLight, but the request is cancelled once the supplied context is done.
+func (b *Bridge) DeleteLightWithContext(ctx context.Context, id string) error {
+	return b.deleteResource(ctx, "/lights/"+id)
+}
 
-	return nil
+// DeleteLightAndReferences removes the specified light from the bridge, along with any references to it.
+// The light is removed from each group and scene containing it, and groups and scenes left without any lights are deleted.
+// Rule actions which target the light are removed; rules with conditions on the light, or without any remaining actions, are deleted.
+func (b *Bridge) DeleteLightAndReferences(id string) error {
+	return b.DeleteLightAndReferencesWithContext(context.Background(), id)
 }
 
-// SetLightState sets the specified light with the supplied light state.
-func (b *Bridge) SetLightState(id string, args *LightStateArg) error {
-	if !b.isAvailable() {
-		return ErrBridgeNotAvailable
-	} else if b.updateInProgress {
-		return ErrBridgeUpdating
+// DeleteLightAndReferencesWithContext is like DeleteLightAndReferences, but the request is cancelled once the supplied context is done.
+func (b *Bridge) DeleteLightAndReferencesWithContext(ctx context.Context, id string) error {
+	groups, err := b.GroupsWithContext(ctx)
+	if err != nil {
+		return err
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/lights/" + id + "/state"
+	for _, group := range groups {
+		lights, found := removeString(group.Lights, id)
+		if !found {
+			continue
+		}
 
-	buf := new(bytes.Buffer)
+		if len(lights) < 1 && group.Type != GroupTypeRoom && group.Type != GroupTypeZone {
+			err = b.DeleteGroupWithContext(ctx, group.ID)
+		} else {
+			var args GroupArg
+			args.SetLights(lights)
 
-	err := json.NewEncoder(buf).Encode(args.args)
-	if err != nil {
-		return err
+			err = b.SetGroupWithContext(ctx, group.ID, &args)
+		}
+
+		if err != nil {
+			return err
+		}
 	}
 
-	req, err := http.NewRequest(http.MethodPut, url, buf)
+	scenes, err := b.ScenesWithContext(ctx)
 	if err != nil {
 		return err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
+	for _, scene := range scenes {
+		lights, found := removeString(scene.Lights, id)
+		if !found {
+			continue
+		}
+
+		if len(lights) < 1 {
+			err = b.DeleteSceneWithContext(ctx, scene.ID)
+		} else if scene.Type != SceneTypeGroup {
+			// The lights of a group scene follow its group, which has already been updated.
+			var args SceneArg
+			args.SetLights(lights)
 
-	resp, err := client.Do(req)
+			err = b.SetSceneWithContext(ctx, scene.ID, &args)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	rules, err := b.RulesWithContext(ctx)
 	if err != nil {
 		return err
 	}
 
-	var respEntries responseEntries
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
+	lightAddress := "/lights/" + id + "/"
 
-	for _, respEntry := range respEntries {
-		var e responseEntry
-		if err = json.Unmarshal(respEntry, &e); err != nil {
-			return err
+	for _, rule := range rules {
+		conditionFound := false
+		for _, condition := range rule.Conditions {
+			if strings.HasPrefix(condition.Address, lightAddress) {
+				conditionFound = true
+				break
+			}
 		}
 
-		if e.Error.Type > 0 {
-			if args.errors == nil {
-				args.errors = make(map[string]ResponseError)
+		var actions []RuleAction
+		for _, action := range rule.Actions {
+			if !strings.HasPrefix(action.Address, lightAddress) {
+				actions = append(actions, action)
 			}
+		}
 
-			keys := strings.Split(e.Error.Address, "/")
-			key := keys[len(keys)-1]
+		actionFound := len(actions) != len(rule.Actions)
 
-			args.errors[key] = e.Error
-		} else {
-			for path, jsonValue := range e.Success {
-				keys := strings.Split(path, "/")
-
-				key := keys[len(keys)-1]
-
-				if key == "xy" {
-					var xyArr []float64
-					if err = json.Unmarshal(*jsonValue, &xyArr); err != nil {
-						return err
-					}
-
-					args.args[key] = xyArr
-				} else if key == "bri" || key == "hue" || key == "sat" || key == "ct" || key == "transitiontime" {
-					var v int
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				} else if key == "on" {
-					var v bool
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				} else if key == "alert" || key == "effect" {
-					var v string
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				}
-			}
+		if conditionFound || (actionFound && len(actions) < 1) {
+			err = b.DeleteRuleWithContext(ctx, rule.ID)
+		} else if actionFound {
+			var args RuleArg
+			args.SetActions(actions)
+
+			err = b.SetRuleWithContext(ctx, rule.ID, &args)
+		}
+
+		if err != nil {
+			return err
 		}
 	}
 
-	return nil
+	return b.DeleteLightWithContext(ctx, id)
 }
 
-// DeleteLight removes the specified light from the bridge.
-func (b *Bridge) DeleteLight(id string) error {
-	return errors.New("delete is not yet supported")
+// removeString returns the supplied values without any instances of the specified value, and whether it was present.
+func removeString(values []string, value string) ([]string, bool) {
+	ret := []string{}
+	found := false
+
+	for _, v := range values {
+		if v == value {
+			found = true
+		} else {
+			ret = append(ret, v)
+		}
+	}
+
+	return ret, found
 }
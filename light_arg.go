@@ -13,6 +13,14 @@ func (l *LightArg) Errors() map[string]ResponseError {
 	return l.errors
 }
 
+// Validate checks the values which have been set, returning ValidationErrors for any which the bridge would reject.
+func (l *LightArg) Validate() error {
+	v := newValidator(l.args)
+	v.length("name", 1, maxNameLength)
+
+	return v.err()
+}
+
 // SetName saves the specified value to be applied.
 func (l *LightArg) SetName(name string) {
 	if l.args == nil {
@@ -33,9 +41,10 @@ func (l *LightArg) Name() string {
 // LightStateArg represents a state setting that can be applied to a light.
 type LightStateArg arg
 
-// Reset clears anything set.
+// Reset clears anything set, along with the results of any increments.
 func (l *LightStateArg) Reset() {
 	l.args = make(map[string]interface{})
+	l.success = nil
 }
 
 // Errors returns any errors encountered when applying the specified setting changes.
@@ -43,6 +52,20 @@ func (l *LightStateArg) Errors() map[string]ResponseError {
 	return l.errors
 }
 
+// Validate checks the values which have been set, returning ValidationErrors for any which the bridge would reject.
+// Increments are limited to the valid range when set, and the transition time is limited by its type.
+func (l *LightStateArg) Validate() error {
+	v := newValidator(l.args)
+	v.intRange("bri", 1, 254)
+	v.intRange("sat", 0, 254)
+	v.intRange("ct", 153, 500)
+	v.xyRange("xy")
+	v.oneOf("alert", "none", "select", "lselect")
+	v.oneOf("effect", "none", "colorloop")
+
+	return v.err()
+}
+
 // SetIsOn saves the specified value to be applied.
 func (l *LightStateArg) SetIsOn(isOn bool) {
 	if l.args == nil {
@@ -69,9 +92,9 @@ func (l *LightStateArg) SetBrightness(brightness uint8) {
 	l.args["bri"] = brightness
 }
 
-// Brightness returns the brightness value, if configured.
+// Brightness returns the brightness value, if configured, or the brightness which resulted from an increment.
 func (l *LightStateArg) Brightness() uint8 {
-	if ret, ok := l.args["bri"].(uint8); ok {
+	if ret, ok := (*arg)(l).value("bri").(uint8); ok {
 		return ret
 	}
 	return 0
@@ -86,9 +109,9 @@ func (l *LightStateArg) SetHue(hue uint16) {
 	l.args["hue"] = hue
 }
 
-// Hue returns the hue value, if configured.
+// Hue returns the hue value, if configured, or the hue which resulted from an increment.
 func (l *LightStateArg) Hue() uint16 {
-	if ret, ok := l.args["hue"].(uint16); ok {
+	if ret, ok := (*arg)(l).value("hue").(uint16); ok {
 		return ret
 	}
 	return 0
@@ -103,9 +126,9 @@ func (l *LightStateArg) SetSaturation(saturation uint8) {
 	l.args["sat"] = saturation
 }
 
-// Saturation returns the saturation value, if configured.
+// Saturation returns the saturation value, if configured, or the saturation which resulted from an increment.
 func (l *LightStateArg) Saturation() uint8 {
-	if ret, ok := l.args["sat"].(uint8); ok {
+	if ret, ok := (*arg)(l).value("sat").(uint8); ok {
 		return ret
 	}
 	return 0
@@ -120,9 +143,9 @@ func (l *LightStateArg) SetXY(xy XY) {
 	l.args["xy"] = [2]float64{xy.X, xy.Y}
 }
 
-// XY returns the XY value, if configured.
+// XY returns the XY value, if configured, or the XY value which resulted from an increment.
 func (l *LightStateArg) XY() XY {
-	if ret, ok := l.args["xy"].([2]float64); ok {
+	if ret, ok := (*arg)(l).value("xy").([2]float64); ok {
 		return XY{X: ret[0], Y: ret[1]}
 	}
 	return XY{}
@@ -137,9 +160,99 @@ func (l *LightStateArg) SetColourTemperature(ct uint16) {
 	l.args["ct"] = ct
 }
 
-// ColourTemperature returns the colour temperature value, if configured.
+// ColourTemperature returns the colour temperature value, if configured, or the colour temperature which resulted from an increment.
 func (l *LightStateArg) ColourTemperature() uint16 {
-	if ret, ok := l.args["ct"].(uint16); ok {
+	if ret, ok := (*arg)(l).value("ct").(uint16); ok {
+		return ret
+	}
+	return 0
+}
+
+// SetBrightnessIncrement saves the specified change to the current brightness to be applied, limited to +/-254.
+// It is ignored by the bridge if a brightness is also set.
+func (l *LightStateArg) SetBrightnessIncrement(inc int16) {
+	if l.args == nil {
+		l.args = make(map[string]interface{})
+	}
+
+	l.args["bri_inc"] = int16(clampIncrement(int64(inc), maxBrightnessIncrement))
+}
+
+// BrightnessIncrement returns the brightness increment, if configured.
+func (l *LightStateArg) BrightnessIncrement() int16 {
+	if ret, ok := l.args["bri_inc"].(int16); ok {
+		return ret
+	}
+	return 0
+}
+
+// SetHueIncrement saves the specified change to the current hue to be applied, limited to +/-65534.
+// It is ignored by the bridge if a hue is also set.
+func (l *LightStateArg) SetHueIncrement(inc int32) {
+	if l.args == nil {
+		l.args = make(map[string]interface{})
+	}
+
+	l.args["hue_inc"] = int32(clampIncrement(int64(inc), maxHueIncrement))
+}
+
+// HueIncrement returns the hue increment, if configured.
+func (l *LightStateArg) HueIncrement() int32 {
+	if ret, ok := l.args["hue_inc"].(int32); ok {
+		return ret
+	}
+	return 0
+}
+
+// SetSaturationIncrement saves the specified change to the current saturation to be applied, limited to +/-254.
+// It is ignored by the bridge if a saturation is also set.
+func (l *LightStateArg) SetSaturationIncrement(inc int16) {
+	if l.args == nil {
+		l.args = make(map[string]interface{})
+	}
+
+	l.args["sat_inc"] = int16(clampIncrement(int64(inc), maxSaturationIncrement))
+}
+
+// SaturationIncrement returns the saturation increment, if configured.
+func (l *LightStateArg) SaturationIncrement() int16 {
+	if ret, ok := l.args["sat_inc"].(int16); ok {
+		return ret
+	}
+	return 0
+}
+
+// SetXYIncrement saves the specified change to the current XY value to be applied, limited to +/-0.5 on each axis.
+// It is ignored by the bridge if an XY value is also set.
+func (l *LightStateArg) SetXYIncrement(inc XY) {
+	if l.args == nil {
+		l.args = make(map[string]interface{})
+	}
+
+	l.args["xy_inc"] = [2]float64{clampXYIncrement(inc.X), clampXYIncrement(inc.Y)}
+}
+
+// XYIncrement returns the XY increment, if configured.
+func (l *LightStateArg) XYIncrement() XY {
+	if ret, ok := l.args["xy_inc"].([2]float64); ok {
+		return XY{X: ret[0], Y: ret[1]}
+	}
+	return XY{}
+}
+
+// SetColourTemperatureIncrement saves the specified change to the current colour temperature to be applied, limited to +/-65534.
+// It is ignored by the bridge if a colour temperature is also set.
+func (l *LightStateArg) SetColourTemperatureIncrement(inc int32) {
+	if l.args == nil {
+		l.args = make(map[string]interface{})
+	}
+
+	l.args["ct_inc"] = int32(clampIncrement(int64(inc), maxColourTemperatureIncrement))
+}
+
+// ColourTemperatureIncrement returns the colour temperature increment, if configured.
+func (l *LightStateArg) ColourTemperatureIncrement() int32 {
+	if ret, ok := l.args["ct_inc"].(int32); ok {
 		return ret
 	}
 	return 0
@@ -158,7 +271,7 @@ func (l *LightStateArg) SetRGB(rgb RGB, lightModel string) {
 
 // RGB returns the RGB value, if configured.
 func (l *LightStateArg) RGB(lightModel string) RGB {
-	if ret, ok := l.args["xy"].([2]float64); ok {
+	if ret, ok := (*arg)(l).value("xy").([2]float64); ok {
 		var rgb RGB
 		rgb.FromXY(XY{X: ret[0], Y: ret[1]}, lightModel)
 		return rgb
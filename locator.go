@@ -1,7 +1,9 @@
 package hue
 
 import (
+	"context"
 	"encoding/json"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
@@ -54,21 +56,36 @@ func (d *Locator) AddStaticAddress(addr string) {
 
 // Run begins running an instance of the Hue locator. Detected bridges will be passed along the supplied channel.
 func (d *Locator) Run(results chan Bridge) {
-	go runStatic(d.staticAddrs, d.incoming)
-	go runUPnP(d.incoming)
-	go runNUPnP(d.incoming)
+	d.RunWithContext(context.Background(), results)
+}
+
+// RunWithContext is like Run, but stops locating bridges once the supplied context is done.
+func (d *Locator) RunWithContext(ctx context.Context, results chan Bridge) {
+	go runStatic(ctx, d.staticAddrs, d.incoming)
+	go runUPnP(ctx, d.incoming)
+	go runNUPnP(ctx, d.incoming)
 
 	for {
-		res := <-d.incoming
+		var res result
+
+		select {
+		case res = <-d.incoming:
+		case <-ctx.Done():
+			return
+		}
 
 		if res.url == nil {
 			continue
 		}
 
 		br := NewBridge("")
-		err := br.InitURL(res.url)
+		err := br.InitURLWithContext(ctx, res.url)
 		if err != nil {
-			log.Fatalf("Unable to validate bridge URL %s: %s\n", res.url.String(), err)
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.Printf("Unable to validate bridge URL %s: %s\n", res.url.String(), err)
 			continue
 		}
 
@@ -80,7 +97,11 @@ func (d *Locator) Run(results chan Bridge) {
 
 			d.profiles[br.ID()] = res.url
 
-			results <- *br
+			select {
+			case results <- *br:
+			case <-ctx.Done():
+				return
+			}
 		} else if !(strings.Contains(currURL.Host, res.url.Host) || strings.Contains(res.url.Host, currURL.Host)) || currURL.Path != res.url.Path || currURL.Scheme != res.url.Scheme {
 			// We don't do a straight value comparison because UPnP returns the port, while nUPnP does not.
 			// So we use the host comparisons to drop the port, then check path and scheme.
@@ -98,7 +119,15 @@ func bridgeDescURLFromIP(ip string) (*url.URL, error) {
 	return url.Parse("http://" + ip + "/description.xml")
 }
 
-func runStatic(addrs []string, results chan result) {
+// sendResult passes the supplied result to the locator, unless the context is done first.
+func sendResult(ctx context.Context, results chan result, r result) {
+	select {
+	case results <- r:
+	case <-ctx.Done():
+	}
+}
+
+func runStatic(ctx context.Context, addrs []string, results chan result) {
 	for _, addr := range addrs {
 		// Skip empty addresses
 		if len(addr) < 1 {
@@ -111,19 +140,22 @@ func runStatic(addrs []string, results chan result) {
 			url:    url,
 			source: SourceStatic,
 		}
-		results <- r
+		sendResult(ctx, results, r)
 	}
 }
 
-func runNUPnP(results chan result) {
+func runNUPnP(ctx context.Context, results chan result) {
 	ticker := time.NewTicker(10 * time.Second)
-	quit := make(chan struct{})
 
 	for {
 		select {
 		case <-ticker.C:
-			res, err := http.Get("https://www.meethue.com/api/nupnp")
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://www.meethue.com/api/nupnp", nil)
+			if err != nil {
+				continue
+			}
 
+			res, err := http.DefaultClient.Do(req)
 			if err != nil {
 				continue
 			}
@@ -138,6 +170,7 @@ func runNUPnP(results chan result) {
 			var body []entry
 
 			err = json.NewDecoder(res.Body).Decode(&body)
+			closeBody(res.Body)
 			if err != nil {
 				continue
 			}
@@ -155,28 +188,77 @@ func runNUPnP(results chan result) {
 					url:    url,
 					source: SourceNUPNP,
 				}
-				results <- r
+				sendResult(ctx, results, r)
 			}
-		case <-quit:
+		case <-ctx.Done():
 			ticker.Stop()
 			return
 		}
 	}
 }
 
-func runUPnP(results chan result) {
+func runUPnP(ctx context.Context, results chan result) {
 	c := make(chan ssdp.Update)
 	srv, reg := ssdp.NewServerAndRegistry()
 	reg.AddListener(c)
-	go runSSDPReceiver(c, results)
+	go runSSDPReceiver(ctx, c, results)
+
+	if err := serveSSDP(ctx, srv); err != nil {
+		// The other sources keep locating bridges, so only UPnP discovery stops, once the receiver has stopped too.
+		log.Printf("Unable to locate bridges using UPnP: %s\n", err)
+		<-ctx.Done()
+	}
+
+	// The receiver has stopped, so drain any updates still being delivered until the registry has let go of the channel.
+	removed := make(chan struct{})
+	go func() {
+		reg.RemoveListener(c)
+		close(removed)
+	}()
 
-	if err := srv.ListenAndServe(); err != nil {
-		log.Fatal("ListenAndServe failed: ", err)
+	for {
+		select {
+		case <-c:
+		case <-removed:
+			return
+		}
+	}
+}
+
+// serveSSDP serves the SSDP server until the context is done, returning the error if it stops before then.
+// The server is served on a socket opened here, rather than using ListenAndServe, so it can be closed once the context is done.
+func serveSSDP(ctx context.Context, srv *ssdp.Server) error {
+	addr, err := net.ResolveUDPAddr("udp", srv.Addr)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.ListenMulticastUDP("udp", srv.Interface, addr)
+	if err != nil {
+		return err
 	}
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	if err = srv.Serve(conn); err != nil && ctx.Err() == nil {
+		return err
+	}
+
+	return nil
 }
 
-func runSSDPReceiver(c <-chan ssdp.Update, results chan result) {
-	for u := range c {
+func runSSDPReceiver(ctx context.Context, c <-chan ssdp.Update, results chan result) {
+	for {
+		var u ssdp.Update
+		select {
+		case u = <-c:
+		case <-ctx.Done():
+			return
+		}
+
 		if u.Entry == nil {
 			continue
 		} else if !strings.Contains(u.Entry.Server, "IpBridge") {
@@ -187,6 +269,6 @@ func runSSDPReceiver(c <-chan ssdp.Update, results chan result) {
 			url:    &u.Entry.Location,
 			source: SourceUPNP,
 		}
-		results <- r
+		sendResult(ctx, results, r)
 	}
 }
@@ -0,0 +1,151 @@
+package hue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// defaultPairRetryInterval is how long PairWithContext waits between attempts if no interval is specified.
+const defaultPairRetryInterval = time.Second
+
+// PairOptions controls how PairWithContext pairs with the bridge.
+type PairOptions struct {
+	// GenerateClientKey requests a client key, used for entertainment streaming, along with the username.
+	GenerateClientKey bool
+
+	// RetryInterval is how long to wait between attempts; it defaults to one second.
+	RetryInterval time.Duration
+
+	// Progress, if set, is called after each attempt which failed because the link button has not been pressed yet.
+	Progress func(attempt int)
+}
+
+// PairTimeoutError is returned if the link button was not pressed before the context passed to PairWithContext was done.
+type PairTimeoutError struct {
+	Attempts int
+	Err      error
+}
+
+// Error describes why pairing failed.
+func (e *PairTimeoutError) Error() string {
+	return "link button was not pressed: " + e.Err.Error()
+}
+
+// Unwrap returns the reason the context was done.
+func (e *PairTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Pair sets up the bridge with a new user.
+// The link button on the bridge must have been pressed shortly beforehand; if not, the ResponseError reported by the bridge is returned.
+// On success the Username of the bridge is set to the new user.
+func (b *Bridge) Pair(appName string, identifier string) error {
+	return b.pair(context.Background(), appName, identifier, false)
+}
+
+// PairWithContext sets up the bridge with a new user, retrying until the link button is pressed or the context is done.
+// On success the Username, and if requested the ClientKey, of the bridge are set.
+func (b *Bridge) PairWithContext(ctx context.Context, appName string, identifier string, opts *PairOptions) error {
+	if opts == nil {
+		opts = &PairOptions{}
+	}
+
+	interval := opts.RetryInterval
+	if interval <= 0 {
+		interval = defaultPairRetryInterval
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := b.pair(ctx, appName, identifier, opts.GenerateClientKey)
+		if err == nil {
+			// The attempt may have succeeded just as the context was done, in which case the bridge is still paired.
+			return nil
+		} else if ctx.Err() != nil {
+			return &PairTimeoutError{Attempts: attempt, Err: ctx.Err()}
+		} else if !errors.Is(err, ErrLinkButtonNotPressed) {
+			return err
+		}
+
+		if opts.Progress != nil {
+			opts.Progress(attempt)
+		}
+
+		select {
+		case <-ctx.Done():
+			return &PairTimeoutError{Attempts: attempt, Err: ctx.Err()}
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (b *Bridge) pair(ctx context.Context, appName string, identifier string, generateClientKey bool) error {
+	b.shared().mu.RLock()
+	baseURL := b.baseURL
+	b.shared().mu.RUnlock()
+
+	if baseURL == nil {
+		return ErrBridgeNotConfigured
+	}
+
+	url := baseURL.String() + "api"
+
+	reqBody := struct {
+		DeviceType        string `json:"devicetype"`
+		GenerateClientKey bool   `json:"generateclientkey,omitempty"`
+	}{
+		DeviceType:        appName + "#" + identifier,
+		GenerateClientKey: generateClientKey,
+	}
+
+	buf := new(bytes.Buffer)
+	err := json.NewEncoder(buf).Encode(reqBody)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, buf)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	res, err := b.httpClient().Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer closeBody(res.Body)
+
+	var respEntries []struct {
+		Success struct {
+			Username  string `json:"username"`
+			ClientKey string `json:"clientkey"`
+		} `json:"success"`
+		Error ResponseError `json:"error"`
+	}
+
+	err = json.NewDecoder(res.Body).Decode(&respEntries)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range respEntries {
+		if e.Error.Type > 0 {
+			return e.Error
+		}
+
+		if len(e.Success.Username) > 0 {
+			b.shared().mu.Lock()
+			b.Username = e.Success.Username
+			b.ClientKey = e.Success.ClientKey
+			b.shared().mu.Unlock()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package hue
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBridge_PairUsername(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The bridge wraps the new username in a list of response entries.
+		w.Write([]byte(`[{"success":{"username":"83b7780291a6ceffbe0bd049104df"}}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if err := bridge.Pair("hue-go", "test"); err != nil {
+		t.Fatalf("Unable to pair: %s\n", err.Error())
+	}
+
+	if bridge.Username != "83b7780291a6ceffbe0bd049104df" {
+		t.Errorf("Expected the username from the response, got %q\n", bridge.Username)
+	}
+}
+
+// cancellingTransport cancels a context once a request to the API has completed, as if its deadline passed just as the
+// response arrived.
+type cancellingTransport struct {
+	cancel context.CancelFunc
+}
+
+func (t *cancellingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err != nil || !strings.HasPrefix(req.URL.Path, "/api") {
+		return resp, err
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	t.cancel()
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func TestBridge_PairWithContextSucceedsAtDeadline(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"success":{"username":"83b7780291a6ceffbe0bd049104df"}}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	bridge := NewBridge("", WithTransport(&cancellingTransport{cancel: cancel}))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if err := bridge.PairWithContext(ctx, "hue-go", "test", nil); err != nil {
+		t.Fatalf("Expected pairing to succeed, got %v\n", err)
+	}
+	if ctx.Err() == nil {
+		t.Errorf("Expected the context to be done once the bridge was paired\n")
+	}
+	if bridge.Username != "83b7780291a6ceffbe0bd049104df" {
+		t.Errorf("Expected the username from the response, got %q\n", bridge.Username)
+	}
+}
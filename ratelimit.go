@@ -0,0 +1,374 @@
+package hue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQueueFull is returned if a command can't be queued because too many commands are already waiting to be sent.
+var ErrQueueFull = errors.New("command queue is full")
+
+const (
+	// DefaultLightsPerSecond is the rate of light commands the bridge tolerates.
+	DefaultLightsPerSecond = 10
+	// DefaultGroupsPerSecond is the rate of group commands the bridge tolerates.
+	DefaultGroupsPerSecond = 1
+	// DefaultMaxQueueDepth is the number of distinct commands which may be waiting to be sent at once.
+	DefaultMaxQueueDepth = 64
+)
+
+// RateLimit configures how quickly light and group commands are sent to the bridge.
+// Any fields which are not set use the defaults.
+type RateLimit struct {
+	// LightsPerSecond is the rate light state changes are sent at.
+	LightsPerSecond float64
+	// GroupsPerSecond is the rate group action changes are sent at.
+	GroupsPerSecond float64
+	// Burst is the number of commands which may be sent at once before the rate applies.
+	Burst int
+	// MaxQueueDepth is the number of distinct commands of each kind which may be waiting to be sent.
+	MaxQueueDepth int
+}
+
+// QueueMetrics reports the activity of the command queues of a bridge.
+type QueueMetrics struct {
+	// Depth is the number of commands currently waiting to be sent.
+	Depth int
+	// Sent is the number of commands sent to the bridge.
+	Sent uint64
+	// Merged is the number of commands which were combined with a command already waiting for the same light or group.
+	Merged uint64
+	// Dropped is the number of commands which were never sent, either because the queue was full or the caller gave up.
+	Dropped uint64
+}
+
+// WithRateLimit queues light state and group action changes, sending them no faster than the bridge can handle.
+// While a change is waiting to be sent, further changes to the same light or group are merged into it, with the most recent value of each property used and any increments added together.
+func WithRateLimit(limit RateLimit) BridgeOption {
+	if limit.LightsPerSecond <= 0 {
+		limit.LightsPerSecond = DefaultLightsPerSecond
+	}
+	if limit.GroupsPerSecond <= 0 {
+		limit.GroupsPerSecond = DefaultGroupsPerSecond
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	if limit.MaxQueueDepth < 1 {
+		limit.MaxQueueDepth = DefaultMaxQueueDepth
+	}
+
+	return func(b *Bridge) {
+		b.lightQueue = newCommandQueue(newTokenBucket(limit.LightsPerSecond, limit.Burst), limit.MaxQueueDepth, b.putState)
+		b.groupQueue = newCommandQueue(newTokenBucket(limit.GroupsPerSecond, limit.Burst), limit.MaxQueueDepth, b.putState)
+	}
+}
+
+// QueueMetrics returns the combined activity of the light and group command queues.
+// All values are zero unless the bridge was created using WithRateLimit.
+func (b *Bridge) QueueMetrics() QueueMetrics {
+	var m QueueMetrics
+
+	for _, q := range []*commandQueue{b.lightQueue, b.groupQueue} {
+		if q == nil {
+			continue
+		}
+
+		qm := q.metrics()
+		m.Depth += qm.Depth
+		m.Sent += qm.Sent
+		m.Merged += qm.Merged
+		m.Dropped += qm.Dropped
+	}
+
+	return m
+}
+
+// tokenBucket limits how often an action can be taken.
+// It is only used by a single goroutine, so isn't safe for concurrent use.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+	}
+}
+
+// refill adds the tokens which have accumulated since the bucket was last used.
+func (tb *tokenBucket) refill(now time.Time) {
+	if !tb.last.IsZero() {
+		tb.tokens += now.Sub(tb.last).Seconds() * tb.rate
+		if tb.tokens > tb.burst {
+			tb.tokens = tb.burst
+		}
+	}
+	tb.last = now
+}
+
+// delay returns how long the caller must wait until a token is available, without consuming it.
+func (tb *tokenBucket) delay(now time.Time) time.Duration {
+	tb.refill(now)
+
+	if tb.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration((1 - tb.tokens) / tb.rate * float64(time.Second))
+}
+
+// take consumes a token, returning how long the caller must wait before acting on it.
+func (tb *tokenBucket) take(now time.Time) time.Duration {
+	tb.refill(now)
+
+	tb.tokens--
+	if tb.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-tb.tokens / tb.rate * float64(time.Second))
+}
+
+// queuedCommand is a state change waiting to be sent, along with the result once it has been.
+type queuedCommand struct {
+	url     string
+	values  map[string]interface{}
+	waiters int
+
+	// ctx is cancelled once every caller waiting for the command has given up, abandoning the request if it has been sent.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	done        chan struct{}
+	respEntries responseEntries
+	err         error
+}
+
+// commandQueue sends state changes at a limited rate, merging changes to the same resource while they wait.
+type commandQueue struct {
+	bucket   *tokenBucket
+	maxDepth int
+	send     func(ctx context.Context, url string, values map[string]interface{}) (responseEntries, error)
+
+	mu      sync.Mutex
+	pending map[string]*queuedCommand
+	order   []string
+	running bool
+
+	sent    uint64
+	merged  uint64
+	dropped uint64
+}
+
+func newCommandQueue(bucket *tokenBucket, maxDepth int, send func(context.Context, string, map[string]interface{}) (responseEntries, error)) *commandQueue {
+	return &commandQueue{
+		bucket:   bucket,
+		maxDepth: maxDepth,
+		send:     send,
+		pending:  make(map[string]*queuedCommand),
+	}
+}
+
+// submit queues the supplied values to be sent to the URL, and waits for the result.
+func (q *commandQueue) submit(ctx context.Context, url string, values map[string]interface{}) (responseEntries, error) {
+	q.mu.Lock()
+
+	cmd, ok := q.pending[url]
+	if ok {
+		mergeValues(cmd.values, values)
+		q.merged++
+	} else if len(q.order) >= q.maxDepth {
+		q.dropped++
+		q.mu.Unlock()
+		return nil, ErrQueueFull
+	} else {
+		cmd = &queuedCommand{
+			url:    url,
+			values: make(map[string]interface{}, len(values)),
+			done:   make(chan struct{}),
+		}
+		cmd.ctx, cmd.cancel = context.WithCancel(context.Background())
+		for key, value := range values {
+			cmd.values[key] = value
+		}
+
+		q.pending[url] = cmd
+		q.order = append(q.order, url)
+	}
+
+	cmd.waiters++
+
+	if !q.running {
+		q.running = true
+		go q.run()
+	}
+
+	q.mu.Unlock()
+
+	select {
+	case <-cmd.done:
+		if cmd.err != nil {
+			return nil, cmd.err
+		}
+
+		return filterEntries(cmd.respEntries, cmd.values, values), nil
+	case <-ctx.Done():
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	cmd.waiters--
+	if cmd.waiters < 1 {
+		// Nobody is waiting for the command any longer, so don't bother sending it, or stop waiting for the bridge if it
+		// has already been sent so the commands behind it aren't held up.
+		if q.pending[url] == cmd {
+			q.remove(url)
+			q.dropped++
+		}
+		cmd.cancel()
+	}
+
+	return nil, ctx.Err()
+}
+
+// mergeValues merges the values of a new command into those of a command which is already queued.
+// As the bridge ignores an increment which is sent along with an absolute value, an increment is applied to a queued
+// absolute value, or added to a queued increment, while a new absolute value replaces any queued increment.
+func mergeValues(pending map[string]interface{}, values map[string]interface{}) {
+	for key, value := range values {
+		if !isIncrement(key) {
+			pending[key] = value
+			delete(pending, key+"_inc")
+			continue
+		}
+
+		name := strings.TrimSuffix(key, "_inc")
+		if _, ok := values[name]; ok {
+			// The increment was already going to be ignored.
+			continue
+		}
+
+		if current, ok := pending[name]; ok {
+			pending[name] = applyIncrement(name, current, value)
+		} else if current, ok := pending[key]; ok {
+			pending[key] = addIncrements(key, current, value)
+		} else {
+			pending[key] = value
+		}
+	}
+}
+
+// filterEntries returns the response entries which concern the values a single caller submitted.
+// The command which was sent may have merged the values of several callers, and each should only be told about its own;
+// entries for a value only another caller submitted are left out, while those which aren't about any value are kept.
+// An increment and its absolute value are treated as the same value, as merging may have folded one into the other.
+func filterEntries(respEntries responseEntries, sent map[string]interface{}, values map[string]interface{}) responseEntries {
+	var ret responseEntries
+
+	for _, respEntry := range respEntries {
+		var e responseEntry
+		if err := json.Unmarshal(respEntry, &e); err != nil {
+			// Leave the entry to be reported when the caller decodes the response.
+			ret = append(ret, respEntry)
+			continue
+		}
+
+		address := e.Error.Address
+		if e.Error.Type < 1 {
+			for a := range e.Success {
+				address = a
+			}
+		}
+
+		keys := strings.Split(address, "/")
+		name := strings.TrimSuffix(keys[len(keys)-1], "_inc")
+
+		if submittedValue(sent, name) && !submittedValue(values, name) {
+			continue
+		}
+
+		ret = append(ret, respEntry)
+	}
+
+	return ret
+}
+
+// submittedValue returns whether the values contain the named value, either as an absolute value or an increment.
+func submittedValue(values map[string]interface{}, name string) bool {
+	if _, ok := values[name]; ok {
+		return true
+	}
+
+	_, ok := values[name+"_inc"]
+	return ok
+}
+
+// run sends the queued commands in order until there are none left.
+func (q *commandQueue) run() {
+	for {
+		q.mu.Lock()
+		if len(q.order) < 1 {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+		q.mu.Unlock()
+
+		// Changes submitted while waiting are merged into the commands already queued.
+		// The token is only taken once a command is sent, so it isn't spent if every queued command is abandoned meanwhile.
+		time.Sleep(q.bucket.delay(time.Now()))
+
+		q.mu.Lock()
+		if len(q.order) < 1 {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+
+		url := q.order[0]
+		cmd := q.pending[url]
+		q.remove(url)
+		q.sent++
+		q.bucket.take(time.Now())
+		q.mu.Unlock()
+
+		cmd.respEntries, cmd.err = q.send(cmd.ctx, cmd.url, cmd.values)
+		cmd.cancel()
+		close(cmd.done)
+	}
+}
+
+// remove takes the command for the specified URL out of the queue; the caller must hold the lock.
+func (q *commandQueue) remove(url string) {
+	delete(q.pending, url)
+
+	for i, u := range q.order {
+		if u == url {
+			q.order = append(q.order[:i], q.order[i+1:]...)
+			break
+		}
+	}
+}
+
+func (q *commandQueue) metrics() QueueMetrics {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return QueueMetrics{
+		Depth:   len(q.order),
+		Sent:    q.sent,
+		Merged:  q.merged,
+		Dropped: q.dropped,
+	}
+}
@@ -0,0 +1,261 @@
+package hue
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestTokenBucket_Take(t *testing.T) {
+	tb := newTokenBucket(10, 2)
+	now := time.Now()
+
+	if d := tb.take(now); d != 0 {
+		t.Errorf("Expected first token to be available immediately, got %s\n", d)
+	}
+	if d := tb.take(now); d != 0 {
+		t.Errorf("Expected burst token to be available immediately, got %s\n", d)
+	}
+	if d := tb.take(now); d != 100*time.Millisecond {
+		t.Errorf("Expected to wait 100ms for the next token, got %s\n", d)
+	}
+	if d := tb.take(now.Add(time.Second)); d != 0 {
+		t.Errorf("Expected tokens to be replenished after a second, got %s\n", d)
+	}
+}
+
+func TestTokenBucket_Delay(t *testing.T) {
+	tb := newTokenBucket(10, 1)
+	now := time.Now()
+
+	if d := tb.delay(now); d != 0 {
+		t.Errorf("Expected a token to be available immediately, got %s\n", d)
+	}
+	if d := tb.delay(now); d != 0 {
+		t.Errorf("Expected the token not to be consumed, got %s\n", d)
+	}
+
+	tb.take(now)
+	if d := tb.delay(now); d != 100*time.Millisecond {
+		t.Errorf("Expected to wait 100ms for the next token, got %s\n", d)
+	}
+}
+
+func TestBridge_SetLightStateRateLimited(t *testing.T) {
+	var conns, requests int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		w.Write([]byte(`[{"success":{"/lights/1/state/bri":200}}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithRateLimit(RateLimit{LightsPerSecond: 20}))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+	atomic.StoreInt64(&requests, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(bri uint8) {
+			defer wg.Done()
+
+			var args LightStateArg
+			args.SetBrightness(bri)
+
+			if err := bridge.SetLightState("1", &args); err != nil {
+				t.Errorf("Unable to set light state: %s\n", err.Error())
+			}
+		}(uint8(i))
+	}
+	wg.Wait()
+
+	m := bridge.QueueMetrics()
+	if m.Sent+m.Merged != 20 {
+		t.Errorf("Expected every command to be sent or merged, got %+v\n", m)
+	}
+	if m.Merged < 1 || uint64(atomic.LoadInt64(&requests)) != m.Sent {
+		t.Errorf("Expected commands to be merged, got %d requests and %+v\n", requests, m)
+	}
+	if m.Depth != 0 || m.Dropped != 0 {
+		t.Errorf("Expected queue to be empty, got %+v\n", m)
+	}
+}
+
+func TestBridge_SetLightStateQueueCancelled(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithRateLimit(RateLimit{LightsPerSecond: 0.1}))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	var args LightStateArg
+	args.SetIsOn(true)
+
+	// The first command uses the only token, so the next has to wait.
+	if err := bridge.SetLightState("1", &args); err != nil {
+		t.Fatalf("Unable to set light state: %s\n", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := bridge.SetLightStateWithContext(ctx, "2", &args); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline to be exceeded, got %v\n", err)
+	}
+
+	if m := bridge.QueueMetrics(); m.Dropped != 1 || m.Depth != 0 {
+		t.Errorf("Expected cancelled command to be dropped, got %+v\n", m)
+	}
+}
+
+func TestCommandQueue_MergedResponses(t *testing.T) {
+	sending := make(chan struct{})
+	release := make(chan struct{})
+	send := func(ctx context.Context, url string, values map[string]interface{}) (responseEntries, error) {
+		if url == "/lights/2/state" {
+			close(sending)
+			<-release
+			return nil, nil
+		}
+
+		return responseEntries{
+			[]byte(`{"error":{"type":7,"address":"/lights/1/state/bri","description":"invalid value"}}`),
+			[]byte(`{"success":{"/lights/1/state/hue":1000}}`),
+			[]byte(`{"error":{"type":201,"address":"/lights/1/state","description":"device is set to off"}}`),
+		}, nil
+	}
+
+	q := newCommandQueue(newTokenBucket(1000, 1), DefaultMaxQueueDepth, send)
+
+	// The light 2 command holds up the queue so the changes to light 1 are merged.
+	go q.submit(context.Background(), "/lights/2/state", map[string]interface{}{"on": true})
+	<-sending
+
+	var wg sync.WaitGroup
+	results := make([]responseEntries, 2)
+	for i, values := range []map[string]interface{}{{"bri": uint8(255)}, {"hue_inc": int32(1000)}} {
+		wg.Add(1)
+		go func(i int, values map[string]interface{}) {
+			defer wg.Done()
+
+			respEntries, err := q.submit(context.Background(), "/lights/1/state", values)
+			if err != nil {
+				t.Errorf("Unable to submit command: %s\n", err.Error())
+			}
+			results[i] = respEntries
+		}(i, values)
+	}
+
+	for q.metrics().Merged < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	wg.Wait()
+
+	bri := &arg{args: map[string]interface{}{"bri": uint8(255)}}
+	if err := bri.applyResponse("/lights/1/state", results[0]); err == nil {
+		t.Errorf("Expected the brightness error to be reported\n")
+	}
+	if len(results[0]) != 2 || bri.errors["bri"].Type != 7 || bri.errors["state"].Type != 201 {
+		t.Errorf("Expected only the brightness and resource errors, got %+v\n", bri.errors)
+	}
+
+	hue := &arg{args: map[string]interface{}{"hue_inc": int32(1000)}}
+	hue.applyResponse("/lights/1/state", results[1])
+	if len(results[1]) != 2 || hue.success["hue"] != uint16(1000) {
+		t.Errorf("Expected the hue result without the brightness error, got %+v and %+v\n", hue.success, hue.errors)
+	}
+	if _, ok := hue.errors["bri"]; ok {
+		t.Errorf("Expected the brightness error not to be reported for the hue change\n")
+	}
+}
+
+func TestBridge_SetLightStateQueueHungBridge(t *testing.T) {
+	release := make(chan struct{})
+
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/testuser/lights/1/state" {
+			// The bridge never responds to changes to the first light.
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+			return
+		}
+
+		w.Write([]byte(`[{"success":{"/lights/2/state/on":true}}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+	defer close(release)
+
+	bridge := NewBridge("testuser", WithRateLimit(RateLimit{LightsPerSecond: 100}))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	var args LightStateArg
+	args.SetIsOn(true)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := bridge.SetLightStateWithContext(ctx, "1", &args); err != context.DeadlineExceeded {
+		t.Errorf("Expected deadline to be exceeded, got %v\n", err)
+	}
+
+	// The abandoned request must not hold up the commands queued behind it.
+	ctx, cancel = context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := bridge.SetLightStateWithContext(ctx, "2", &args); err != nil {
+		t.Errorf("Unable to set light state: %s\n", err.Error())
+	}
+}
+
+func TestCommandQueue_AbandonedCommandToken(t *testing.T) {
+	send := func(ctx context.Context, url string, values map[string]interface{}) (responseEntries, error) {
+		return nil, nil
+	}
+
+	q := newCommandQueue(newTokenBucket(5, 1), DefaultMaxQueueDepth, send)
+
+	// The first command uses the only token, so the next has to wait for 200ms.
+	if _, err := q.submit(context.Background(), "/lights/1/state", map[string]interface{}{"on": true}); err != nil {
+		t.Fatalf("Unable to submit command: %s\n", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := q.submit(ctx, "/lights/2/state", map[string]interface{}{"on": true}); err != context.DeadlineExceeded {
+		t.Fatalf("Expected deadline to be exceeded, got %v\n", err)
+	}
+
+	// Once the token has been replenished, the abandoned command must not have spent it.
+	time.Sleep(300 * time.Millisecond)
+
+	start := time.Now()
+	if _, err := q.submit(context.Background(), "/lights/3/state", map[string]interface{}{"on": true}); err != nil {
+		t.Fatalf("Unable to submit command: %s\n", err.Error())
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Expected the command to be sent immediately, took %s\n", elapsed)
+	}
+}
@@ -0,0 +1,340 @@
+package hue
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrNoRecording is returned by a ReplayTransport if no recorded interaction matches the request.
+var ErrNoRecording = errors.New("no recorded interaction matches request")
+
+// redactedUsername replaces the username in recorded interactions.
+const redactedUsername = "REDACTED"
+
+var (
+	// apiPathPattern matches the username in an API path, such as /api/<username>/lights.
+	apiPathPattern = regexp.MustCompile(`^/api/[^/]+`)
+	// credentialPattern matches the credentials returned when pairing.
+	credentialPattern = regexp.MustCompile(`"(username|clientkey)"\s*:\s*"[^"]*"`)
+	// stringPattern matches each string, including the keys of objects, in a JSON body.
+	stringPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+	// segmentPattern matches each segment of a path, along with any words following it, such as in "/config/whitelist/<key> deleted".
+	segmentPattern = regexp.MustCompile(`[^/\s]+`)
+)
+
+// Interaction is a single request made to the bridge, along with the response it returned.
+type Interaction struct {
+	Method       string `json:"method"`
+	Path         string `json:"path"`
+	RequestBody  string `json:"requestBody,omitempty"`
+	StatusCode   int    `json:"statusCode"`
+	ContentType  string `json:"contentType,omitempty"`
+	ResponseBody string `json:"responseBody"`
+}
+
+// redactPath replaces the username in the supplied request path.
+func redactPath(path string) string {
+	return apiPathPattern.ReplaceAllString(path, "/api/"+redactedUsername)
+}
+
+// redactValue returns the redacted form of the supplied value if it is one of the usernames.
+// A value which is a path, such as the address of a rule action, has each of its segments which is a username redacted.
+func redactValue(value string, usernames map[string]string) string {
+	if redacted, ok := usernames[value]; ok {
+		return redacted
+	} else if !strings.HasPrefix(value, "/") {
+		return value
+	}
+
+	return segmentPattern.ReplaceAllStringFunc(value, func(segment string) string {
+		if redacted, ok := usernames[segment]; ok {
+			return redacted
+		}
+		return segment
+	})
+}
+
+// redactBody replaces the supplied usernames with their redacted form, along with any credentials returned when pairing, in the supplied body.
+// Only whole strings, or whole segments of paths, are replaced, so a username which is part of another value is left alone.
+func redactBody(body string, usernames map[string]string) string {
+	body = stringPattern.ReplaceAllStringFunc(body, func(literal string) string {
+		var value string
+		if err := json.Unmarshal([]byte(literal), &value); err != nil {
+			return literal
+		}
+
+		redacted := redactValue(value, usernames)
+		if redacted == value {
+			return literal
+		}
+
+		encoded, err := json.Marshal(redacted)
+		if err != nil {
+			return literal
+		}
+		return string(encoded)
+	})
+
+	return credentialPattern.ReplaceAllString(body, `"$1":"`+redactedUsername+`"`)
+}
+
+// whitelistKeys returns the usernames of the whitelist in the supplied body, which is returned as part of the config
+// and the full state of the bridge.
+func whitelistKeys(body []byte) []string {
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(body, &values); err != nil {
+		return nil
+	}
+
+	if config, ok := values["config"]; ok {
+		return whitelistKeys(config)
+	}
+
+	var whitelist map[string]json.RawMessage
+	if err := json.Unmarshal(values["whitelist"], &whitelist); err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(whitelist))
+	for key := range whitelist {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// RecordingTransport records each request made through it, along with the response, so they can be replayed by a ReplayTransport.
+// The username, and those of any other apps on the whitelist of the bridge, are redacted from everything recorded.
+type RecordingTransport struct {
+	// Transport performs the requests; http.DefaultTransport is used if it is nil.
+	Transport http.RoundTripper
+
+	mu           sync.Mutex
+	usernames    map[string]string
+	interactions []Interaction
+}
+
+// RoundTrip performs the request and records it along with the response.
+func (t *RecordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.record(t.Transport, req)
+}
+
+// innerTransport returns the transport requests are performed with, which may be nil.
+func (t *RecordingTransport) innerTransport() http.RoundTripper {
+	return t.Transport
+}
+
+// withTransport returns a transport which records into this recorder, but performs requests with the supplied transport.
+func (t *RecordingTransport) withTransport(transport http.RoundTripper) http.RoundTripper {
+	return &recordingWrapper{recorder: t, transport: transport}
+}
+
+// record performs the request using the supplied transport, recording it along with the response.
+func (t *RecordingTransport) record(transport http.RoundTripper, req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.usernames == nil {
+		t.usernames = make(map[string]string)
+	}
+	parts := strings.Split(req.URL.Path, "/")
+	if len(parts) > 2 && parts[1] == "api" && len(parts[2]) > 0 {
+		t.usernames[parts[2]] = redactedUsername
+	}
+	// Other apps can be removed from the whitelist without it having been retrieved first.
+	if len(parts) > 5 && parts[3] == "config" && parts[4] == "whitelist" {
+		t.addUsername(parts[5])
+	}
+	for _, key := range whitelistKeys(respBody) {
+		t.addUsername(key)
+	}
+
+	t.interactions = append(t.interactions, Interaction{
+		Method:       req.Method,
+		Path:         redactValue(redactPath(req.URL.Path), t.usernames),
+		RequestBody:  redactBody(string(reqBody), t.usernames),
+		StatusCode:   resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ResponseBody: redactBody(string(respBody), t.usernames),
+	})
+
+	return resp, nil
+}
+
+// addUsername redacts the supplied username from everything recorded; the caller must hold the lock.
+// Each username is kept distinct so the whitelist still decodes to the same number of entries.
+func (t *RecordingTransport) addUsername(username string) {
+	if _, ok := t.usernames[username]; ok || len(username) < 1 {
+		return
+	}
+
+	t.usernames[username] = redactedUsername + "-" + strconv.Itoa(len(t.usernames))
+}
+
+// recordingWrapper records requests into a RecordingTransport, while performing them with a different transport.
+type recordingWrapper struct {
+	recorder  *RecordingTransport
+	transport http.RoundTripper
+}
+
+// RoundTrip performs the request and records it along with the response.
+func (w *recordingWrapper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return w.recorder.record(w.transport, req)
+}
+
+// Interactions returns the interactions recorded so far, in the order they were made.
+func (t *RecordingTransport) Interactions() []Interaction {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Interaction(nil), t.interactions...)
+}
+
+// Save writes the interactions recorded so far to the supplied writer.
+func (t *RecordingTransport) Save(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(t.Interactions())
+}
+
+// SaveFile writes the interactions recorded so far to the specified file.
+func (t *RecordingTransport) SaveFile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err = t.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// ReplayTransport serves previously recorded interactions instead of contacting a bridge.
+// Requests are matched against the recorded method and path, ignoring the host and username. If a request was recorded
+// more than once the responses are served in the order they were recorded, and the last one is repeated once they run out.
+type ReplayTransport struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	next         map[string]int
+}
+
+// NewReplayTransport creates a transport which serves the supplied interactions.
+func NewReplayTransport(interactions []Interaction) *ReplayTransport {
+	return &ReplayTransport{
+		interactions: interactions,
+		next:         make(map[string]int),
+	}
+}
+
+// LoadReplayTransport creates a transport which serves the interactions saved to the supplied reader by a RecordingTransport.
+func LoadReplayTransport(r io.Reader) (*ReplayTransport, error) {
+	var interactions []Interaction
+	if err := json.NewDecoder(r).Decode(&interactions); err != nil {
+		return nil, err
+	}
+
+	return NewReplayTransport(interactions), nil
+}
+
+// LoadReplayFile creates a transport which serves the interactions saved to the specified file by a RecordingTransport.
+func LoadReplayFile(path string) (*ReplayTransport, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return LoadReplayTransport(f)
+}
+
+// RoundTrip serves the recorded response matching the request.
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		closeBody(req.Body)
+	}
+
+	path := redactPath(req.URL.Path)
+	key := req.Method + " " + path
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var matches []Interaction
+	for _, interaction := range t.interactions {
+		if interaction.Method == req.Method && interaction.Path == path {
+			matches = append(matches, interaction)
+		}
+	}
+
+	if len(matches) < 1 {
+		return nil, ErrNoRecording
+	}
+
+	idx := t.next[key]
+	if idx >= len(matches) {
+		idx = len(matches) - 1
+	} else {
+		t.next[key] = idx + 1
+	}
+
+	interaction := matches[idx]
+
+	resp := &http.Response{
+		Status:        strconv.Itoa(interaction.StatusCode) + " " + http.StatusText(interaction.StatusCode),
+		StatusCode:    interaction.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          ioutil.NopCloser(strings.NewReader(interaction.ResponseBody)),
+		ContentLength: int64(len(interaction.ResponseBody)),
+		Request:       req,
+	}
+
+	if len(interaction.ContentType) > 0 {
+		resp.Header.Set("Content-Type", interaction.ContentType)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,166 @@
+package hue
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRecordingTransport_Replay(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"1":{"name":"Hue lamp 1","type":"Extended color light","modelid":"LCT001","state":{"on":true,"bri":144,"colormode":"ct","ct":201}}}`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	recorder := &RecordingTransport{}
+
+	bridge := NewBridge("secretusername", WithTransport(recorder))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if _, err := bridge.Lights(); err != nil {
+		t.Fatalf("Unable to retrieve lights: %s\n", err.Error())
+	}
+
+	var buf bytes.Buffer
+	if err := recorder.Save(&buf); err != nil {
+		t.Fatalf("Unable to save recording: %s\n", err.Error())
+	}
+
+	if strings.Contains(buf.String(), "secretusername") {
+		t.Errorf("Expected username to be redacted from recording:\n%s\n", buf.String())
+	}
+
+	replay, err := LoadReplayTransport(&buf)
+	if err != nil {
+		t.Fatalf("Unable to load recording: %s\n", err.Error())
+	}
+
+	replayed := NewBridge("otheruser", WithTransport(replay))
+	if err = replayed.InitIP("10.0.0.1"); err != nil {
+		t.Fatalf("Unable to initialize replayed bridge: %s\n", err.Error())
+	}
+
+	lights, err := replayed.Lights()
+	if err != nil {
+		t.Fatalf("Unable to retrieve replayed lights: %s\n", err.Error())
+	} else if len(lights) != 1 || lights[0].Name != "Hue lamp 1" || lights[0].State.Brightness != 144 {
+		t.Errorf("Unexpected replayed lights: %+v\n", lights)
+	}
+
+	if _, err = replayed.Sensors(); err == nil {
+		t.Errorf("Expected unrecorded request to fail\n")
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	body := redactBody(`[{"success":{"username":"abc123","clientkey":"DEF456"}}]`, nil)
+	if strings.Contains(body, "abc123") || strings.Contains(body, "DEF456") {
+		t.Errorf("Expected pairing credentials to be redacted, got %s\n", body)
+	}
+}
+
+func TestRedactBody_WholeValues(t *testing.T) {
+	usernames := map[string]string{"abc123": redactedUsername}
+
+	body := redactBody(`{"name":"abc1234","owner":"abc123","address":"/api/abc123/lights/1/state"}`, usernames)
+	if body != `{"name":"abc1234","owner":"REDACTED","address":"/api/REDACTED/lights/1/state"}` {
+		t.Errorf("Expected only whole values and path segments to be redacted, got %s\n", body)
+	}
+}
+
+func TestRecordingTransport_WhitelistDelete(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"success":"/config/whitelist/othersecretkey123 deleted"}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	recorder := &RecordingTransport{}
+
+	bridge := NewBridge("secretusername", WithTransport(recorder))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if err := bridge.DeleteUser("othersecretkey123"); err != nil {
+		t.Fatalf("Unable to delete user: %s\n", err.Error())
+	}
+
+	var buf bytes.Buffer
+	if err := recorder.Save(&buf); err != nil {
+		t.Fatalf("Unable to save recording: %s\n", err.Error())
+	}
+
+	for _, key := range []string{"secretusername", "othersecretkey123"} {
+		if strings.Contains(buf.String(), key) {
+			t.Errorf("Expected %s to be redacted from recording:\n%s\n", key, buf.String())
+		}
+	}
+
+	interactions := recorder.Interactions()
+	if last := interactions[len(interactions)-1]; last.Path != "/api/REDACTED/config/whitelist/REDACTED-1" {
+		t.Errorf("Expected the whitelist key to be redacted from the path, got %s\n", last.Path)
+	} else if last.ResponseBody != `[{"success":"/config/whitelist/REDACTED-1 deleted"}]` {
+		t.Errorf("Expected the whitelist key to be redacted from the response, got %s\n", last.ResponseBody)
+	}
+}
+
+func TestRecordingTransport_Whitelist(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"Philips hue","whitelist":{"secretusername":{"name":"hue-go"},"otherappkey1":{"name":"app#phone"},"otherappkey2":{"name":"app#tablet"}}}`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	recorder := &RecordingTransport{}
+
+	bridge := NewBridge("secretusername", WithTransport(recorder))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if _, err := bridge.Config(); err != nil {
+		t.Fatalf("Unable to retrieve config: %s\n", err.Error())
+	}
+
+	var buf bytes.Buffer
+	if err := recorder.Save(&buf); err != nil {
+		t.Fatalf("Unable to save recording: %s\n", err.Error())
+	}
+
+	for _, key := range []string{"secretusername", "otherappkey1", "otherappkey2"} {
+		if strings.Contains(buf.String(), key) {
+			t.Errorf("Expected %s to be redacted from recording:\n%s\n", key, buf.String())
+		}
+	}
+
+	replay, err := LoadReplayTransport(&buf)
+	if err != nil {
+		t.Fatalf("Unable to load recording: %s\n", err.Error())
+	}
+
+	replayed := NewBridge("otheruser", WithTransport(replay))
+	if err = replayed.InitIP("10.0.0.1"); err != nil {
+		t.Fatalf("Unable to initialize replayed bridge: %s\n", err.Error())
+	}
+
+	config, err := replayed.Config()
+	if err != nil {
+		t.Fatalf("Unable to retrieve replayed config: %s\n", err.Error())
+	} else if len(config.Whitelist) != 3 {
+		t.Errorf("Expected each whitelist entry to be kept, got %+v\n", config.Whitelist)
+	}
+}
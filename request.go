@@ -0,0 +1,243 @@
+package hue
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// send encodes the supplied body as JSON and sends it to the specified URL, returning the response entries.
+func (b *Bridge) send(ctx context.Context, method string, url string, body interface{}) (responseEntries, error) {
+	buf := new(bytes.Buffer)
+
+	err := json.NewEncoder(buf).Encode(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := b.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(resp.Body)
+
+	var respEntries responseEntries
+	err = json.NewDecoder(resp.Body).Decode(&respEntries)
+	if err != nil {
+		return nil, err
+	}
+
+	return respEntries, nil
+}
+
+// decodeResponse decodes the response to a GET request into the supplied value.
+// The bridge reports a failure, such as an unauthorized user, as an array of errors in place of the resource requested,
+// in which case the first ResponseError is returned.
+func decodeResponse(body io.Reader, v interface{}) error {
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		var respEntries []responseEntry
+		if err = json.Unmarshal(trimmed, &respEntries); err == nil {
+			for _, e := range respEntries {
+				if e.Error.Type > 0 {
+					return e.Error
+				}
+			}
+		}
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// update sends the values set in the supplied args to the specified path, relative to the API user, and saves the result into the args.
+func (b *Bridge) update(ctx context.Context, path string, args *arg) error {
+	respEntries, err := b.send(ctx, http.MethodPut, b.apiURL()+path, args.args)
+	if err != nil {
+		return err
+	}
+
+	return args.applyResponse(path, respEntries)
+}
+
+// do sends the supplied body to the specified path, relative to the API user, returning the first error reported by the bridge.
+func (b *Bridge) do(ctx context.Context, method string, path string, body interface{}) error {
+	respEntries, err := b.send(ctx, method, b.apiURL()+path, body)
+	if err != nil {
+		return err
+	}
+
+	for _, respEntry := range respEntries {
+		var e responseEntry
+		if err = json.Unmarshal(respEntry, &e); err != nil {
+			return err
+		}
+
+		if e.Error.Type > 0 {
+			return e.Error
+		}
+	}
+
+	return nil
+}
+
+// create sends the supplied body to the specified path, relative to the API user, returning the ID of the new resource.
+func (b *Bridge) create(ctx context.Context, path string, body interface{}) (string, error) {
+	respEntries, err := b.send(ctx, http.MethodPost, b.apiURL()+path, body)
+	if err != nil {
+		return "", err
+	}
+
+	var id string
+	for _, respEntry := range respEntries {
+		var e responseEntry
+		if err = json.Unmarshal(respEntry, &e); err != nil {
+			return "", err
+		}
+
+		if e.Error.Type > 0 {
+			return "", e.Error
+		}
+
+		if jsonValue, ok := e.Success["id"]; ok && jsonValue != nil {
+			if err = json.Unmarshal(*jsonValue, &id); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return id, nil
+}
+
+// applyResponse saves the per-key results of a change made to the resource at the specified path into the args.
+// Each value the bridge reports as applied replaces the value which was sent, so the args reflect any values the bridge
+// clamped; errors are keyed by the last element of their address.
+func (a *arg) applyResponse(path string, respEntries responseEntries) error {
+	// Only report the errors and results from this request.
+	a.errors = nil
+	a.success = nil
+
+	for _, respEntry := range respEntries {
+		var e responseEntry
+		if err := json.Unmarshal(respEntry, &e); err != nil {
+			return err
+		}
+
+		if e.Error.Type > 0 {
+			if a.errors == nil {
+				a.errors = make(map[string]ResponseError)
+			}
+
+			keys := strings.Split(e.Error.Address, "/")
+			key := keys[len(keys)-1]
+
+			a.errors[key] = e.Error
+			continue
+		}
+
+		for address, jsonValue := range e.Success {
+			if jsonValue == nil {
+				continue
+			}
+
+			keys := addressKeys(path, address)
+			if len(keys) == 1 && a.isIncrementResult(keys[0]) {
+				if err := a.applyIncrementResult(keys[0], *jsonValue); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := applyValue(a.args, keys, *jsonValue); err != nil {
+				return err
+			}
+		}
+	}
+
+	return newResponseErrors(a.errors)
+}
+
+// addressKeys returns the keys of the value at the supplied address, relative to the path of the resource which was changed.
+// Addresses outside of the path are reduced to their last element.
+func addressKeys(path string, address string) []string {
+	if strings.HasPrefix(address, path+"/") {
+		return strings.Split(address[len(path)+1:], "/")
+	}
+
+	keys := strings.Split(address, "/")
+	return keys[len(keys)-1:]
+}
+
+// applyValue replaces the value stored under the supplied keys with the encoded value, decoded into the same type.
+// Values which weren't sent are left alone, so the args only ever hold what was requested.
+func applyValue(values map[string]interface{}, keys []string, jsonValue json.RawMessage) error {
+	current, ok := values[keys[0]]
+	if !ok || current == nil {
+		return nil
+	}
+
+	if len(keys) > 1 {
+		if nested, ok := current.(map[string]interface{}); ok {
+			return applyValue(nested, keys[1:], jsonValue)
+		}
+
+		return nil
+	}
+
+	v := reflect.New(reflect.TypeOf(current))
+	if err := json.Unmarshal(jsonValue, v.Interface()); err != nil {
+		return err
+	}
+
+	values[keys[0]] = v.Elem().Interface()
+	return nil
+}
+
+// isIncrementResult returns whether the specified key is the absolute value resulting from an increment which was sent.
+func (a *arg) isIncrementResult(key string) bool {
+	_, sent := a.args[key]
+	_, inc := a.args[key+"_inc"]
+	_, ok := incrementResults[key]
+
+	return !sent && inc && ok
+}
+
+// applyIncrementResult saves the absolute value resulting from an increment.
+// It is kept apart from the values to send, as the bridge ignores an increment which is sent along with an absolute value.
+func (a *arg) applyIncrementResult(key string, jsonValue json.RawMessage) error {
+	v := reflect.New(reflect.TypeOf(incrementResults[key]))
+	if err := json.Unmarshal(jsonValue, v.Interface()); err != nil {
+		return err
+	}
+
+	if a.success == nil {
+		a.success = make(map[string]interface{})
+	}
+
+	a.success[key] = v.Elem().Interface()
+	return nil
+}
+
+// value returns the value of the specified key which will be sent, or otherwise the result of the most recent increment.
+func (a *arg) value(key string) interface{} {
+	if v, ok := a.args[key]; ok {
+		return v
+	}
+
+	return a.success[key]
+}
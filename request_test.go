@@ -0,0 +1,113 @@
+package hue
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBridge_SetLightStateApplied(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"success":{"/lights/1/state/on":true}},
+			{"success":{"/lights/1/state/bri":254}},
+			{"success":{"/lights/1/state/ct":153}},
+			{"success":{"/lights/1/state/xy":[0.3,0.4]}},
+			{"success":{"/lights/1/state/alert":"select"}}
+		]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	var args LightStateArg
+	args.SetIsOn(true)
+	args.SetBrightness(254)
+	args.SetColourTemperature(100)
+	args.SetXY(XY{X: 0.31, Y: 0.42})
+
+	if err := bridge.SetLightState("1", &args); err != nil {
+		t.Fatalf("Unable to set light state: %s\n", err.Error())
+	}
+
+	if !args.IsOn() || args.Brightness() != 254 {
+		t.Errorf("Expected the light to be on at brightness 254, got %t and %d\n", args.IsOn(), args.Brightness())
+	}
+	if args.ColourTemperature() != 153 {
+		t.Errorf("Expected the clamped colour temperature of 153, got %d\n", args.ColourTemperature())
+	}
+	if xy := args.XY(); xy.X != 0.3 || xy.Y != 0.4 {
+		t.Errorf("Expected the applied xy of 0.3, 0.4, got %v\n", xy)
+	}
+	if len(args.Alert()) > 0 {
+		t.Errorf("Expected only the values which were sent, got alert %s\n", args.Alert())
+	}
+}
+
+func TestBridge_SetSensorConfigApplied(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"success":{"/sensors/2/config/battery":100}},
+			{"success":{"/sensors/2/config/sunriseoffset":-30}}
+		]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	var args SensorConfigArg
+	args.SetBatteryLevel(120)
+	args.SetSunriseOffset(-30)
+
+	if err := bridge.SetSensorConfig("2", &args); err != nil {
+		t.Fatalf("Unable to set sensor config: %s\n", err.Error())
+	}
+
+	if args.BatteryLevel() != 100 || args.SunriseOffset() != -30 {
+		t.Errorf("Expected the applied battery level and offset, got %d and %d\n", args.BatteryLevel(), args.SunriseOffset())
+	}
+}
+
+func TestBridge_GetUnauthorizedUser(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"error":{"type":1,"address":"/","description":"unauthorized user"}}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("baduser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	gets := map[string]func() error{
+		"Lights":    func() error { _, err := bridge.Lights(); return err },
+		"Light":     func() error { _, err := bridge.Light("1"); return err },
+		"NewLights": func() error { _, _, err := bridge.NewLights(); return err },
+		"Config":    func() error { _, err := bridge.Config(); return err },
+		"FullState": func() error { _, err := bridge.FullState(); return err },
+		"Groups":    func() error { _, err := bridge.Groups(); return err },
+		"Schedules": func() error { _, err := bridge.Schedules(); return err },
+		"Sensor":    func() error { _, err := bridge.Sensor("1"); return err },
+	}
+
+	for name, get := range gets {
+		if err := get(); !errors.Is(err, ErrUnauthorizedUser) {
+			t.Errorf("Expected %s to report an unauthorized user, got %v\n", name, err)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package hue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidResourceReference is returned if a resource address cannot be parsed.
+	ErrInvalidResourceReference = errors.New("invalid resource reference")
+	// ErrUnknownResourceKind is returned if a resource reference refers to a kind of resource which cannot be retrieved.
+	ErrUnknownResourceKind = errors.New("unknown resource kind")
+)
+
+const (
+	// ResourceKindLight references a light.
+	ResourceKindLight = "lights"
+	// ResourceKindGroup references a group.
+	ResourceKindGroup = "groups"
+	// ResourceKindScene references a scene.
+	ResourceKindScene = "scenes"
+	// ResourceKindSchedule references a schedule.
+	ResourceKindSchedule = "schedules"
+	// ResourceKindSensor references a sensor.
+	ResourceKindSensor = "sensors"
+	// ResourceKindRule references a rule.
+	ResourceKindRule = "rules"
+	// ResourceKindResourceLink references another resource link.
+	ResourceKindResourceLink = "resourcelinks"
+)
+
+// ResourceReference identifies a single resource on the bridge, such as /sensors/3.
+type ResourceReference struct {
+	Kind string
+	ID   string
+}
+
+// ParseResourceReference converts a resource address into a reference.
+func ParseResourceReference(address string) (ResourceReference, error) {
+	parts := strings.Split(strings.TrimPrefix(address, "/"), "/")
+	if len(parts) != 2 || len(parts[0]) < 1 || len(parts[1]) < 1 {
+		return ResourceReference{}, ErrInvalidResourceReference
+	}
+
+	return ResourceReference{Kind: parts[0], ID: parts[1]}, nil
+}
+
+// String returns the resource address of the reference.
+func (r ResourceReference) String() string {
+	return "/" + r.Kind + "/" + r.ID
+}
+
+// MarshalJSON encodes the reference as a resource address.
+func (r ResourceReference) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.String())
+}
+
+// UnmarshalJSON decodes the reference from a resource address.
+func (r *ResourceReference) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	ret, err := ParseResourceReference(s)
+	if err != nil {
+		return err
+	}
+
+	*r = ret
+	return nil
+}
+
+// ResourceLink represents a collection of resources which belong together, such as the sensors and rules of a single automation.
+type ResourceLink struct {
+	ID          string
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Type        string              `json:"type"`
+	ClassID     uint16              `json:"classid"`
+	Owner       string              `json:"owner"`
+	Recycle     bool                `json:"recycle"`
+	Links       []ResourceReference `json:"links"`
+}
+
+// Resolve retrieves the resource referenced.
+// The returned value is a Light, Group, Scene, Schedule, Sensor, Rule or ResourceLink depending on the kind of the reference.
+func (b *Bridge) Resolve(ref ResourceReference) (interface{}, error) {
+	return b.ResolveWithContext(context.Background(), ref)
+}
+
+// ResolveWithContext is like Resolve, but the request is cancelled once the supplied context is done.
+func (b *Bridge) ResolveWithContext(ctx context.Context, ref ResourceReference) (interface{}, error) {
+	switch ref.Kind {
+	case ResourceKindLight:
+		return b.LightWithContext(ctx, ref.ID)
+	case ResourceKindGroup:
+		return b.GroupWithContext(ctx, ref.ID)
+	case ResourceKindScene:
+		return b.SceneWithContext(ctx, ref.ID)
+	case ResourceKindSchedule:
+		return b.ScheduleWithContext(ctx, ref.ID)
+	case ResourceKindSensor:
+		return b.SensorWithContext(ctx, ref.ID)
+	case ResourceKindRule:
+		return b.RuleWithContext(ctx, ref.ID)
+	case ResourceKindResourceLink:
+		return b.ResourceLinkWithContext(ctx, ref.ID)
+	}
+
+	return nil, ErrUnknownResourceKind
+}
+
+// ResourceLinks returns the collection of resource links configured on the bridge.
+func (b *Bridge) ResourceLinks() ([]ResourceLink, error) {
+	return b.ResourceLinksWithContext(context.Background())
+}
+
+// ResourceLinksWithContext is like ResourceLinks, but the request is cancelled once the supplied context is done.
+func (b *Bridge) ResourceLinksWithContext(ctx context.Context) ([]ResourceLink, error) {
+	if !b.isAvailable() {
+		return nil, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return nil, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/resourcelinks"
+
+	res, err := b.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(res.Body)
+
+	var respBody map[string]ResourceLink
+
+	err = decodeResponse(res.Body, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var links []ResourceLink
+	for id, link := range respBody {
+		link.ID = id
+
+		links = append(links, link)
+	}
+
+	return links, nil
+}
+
+// ResourceLink returns a single resource link from the bridge.
+func (b *Bridge) ResourceLink(id string) (ResourceLink, error) {
+	return b.ResourceLinkWithContext(context.Background(), id)
+}
+
+// ResourceLinkWithContext is like ResourceLink, but the request is cancelled once the supplied context is done.
+func (b *Bridge) ResourceLinkWithContext(ctx context.Context, id string) (ResourceLink, error) {
+	var link ResourceLink
+
+	if !b.isAvailable() {
+		return link, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return link, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/resourcelinks/" + id
+
+	resp, err := b.get(ctx, url)
+	if err != nil {
+		return link, err
+	}
+
+	defer closeBody(resp.Body)
+
+	err = decodeResponse(resp.Body, &link)
+	if err != nil {
+		return link, err
+	}
+
+	link.ID = id
+
+	return link, nil
+}
+
+// CreateResourceLink adds a new resource link to the bridge.
+// The name, description, class ID, recycle and links fields of the supplied resource link are used; the ID is set once the link is created.
+func (b *Bridge) CreateResourceLink(link *ResourceLink) error {
+	return b.CreateResourceLinkWithContext(context.Background(), link)
+}
+
+// CreateResourceLinkWithContext is like CreateResourceLink, but the request is cancelled once the supplied context is done.
+func (b *Bridge) CreateResourceLinkWithContext(ctx context.Context, link *ResourceLink) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	reqBody := struct {
+		Name        string              `json:"name"`
+		Description string              `json:"description,omitempty"`
+		Type        string              `json:"type"`
+		ClassID     uint16              `json:"classid"`
+		Recycle     bool                `json:"recycle"`
+		Links       []ResourceReference `json:"links"`
+	}{
+		Name:        link.Name,
+		Description: link.Description,
+		Type:        "Link",
+		ClassID:     link.ClassID,
+		Recycle:     link.Recycle,
+		Links:       link.Links,
+	}
+
+	if reqBody.Links == nil {
+		reqBody.Links = []ResourceReference{}
+	}
+
+	id, err := b.create(ctx, "/resourcelinks", reqBody)
+	if err != nil {
+		return err
+	}
+
+	link.ID = id
+	return nil
+}
+
+// SetResourceLink updates the attributes of the specified resource link.
+func (b *Bridge) SetResourceLink(id string, args *ResourceLinkArg) error {
+	return b.SetResourceLinkWithContext(context.Background(), id, args)
+}
+
+// SetResourceLinkWithContext is like SetResourceLink, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetResourceLinkWithContext(ctx context.Context, id string, args *ResourceLinkArg) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	if err := b.validate(args); err != nil {
+		return err
+	}
+
+	return b.update(ctx, "/resourcelinks/"+id, (*arg)(args))
+}
+
+// DeleteResourceLink removes the specified resource link from the bridge.
+// The linked resources themselves are not removed.
+func (b *Bridge) DeleteResourceLink(id string) error {
+	return b.DeleteResourceLinkWithContext(context.Background(), id)
+}
+
+// DeleteResourceLinkWithContext is like DeleteResourceLink, but the request is cancelled once the supplied context is done.
+func (b *Bridge) DeleteResourceLinkWithContext(ctx context.Context, id string) error {
+	return b.deleteResource(ctx, "/resourcelinks/"+id)
+}
@@ -0,0 +1,76 @@
+package hue
+
+// ResourceLinkArg represents a configuration argument that can be made to a resource link.
+type ResourceLinkArg arg
+
+// Reset clears any set configuration options.
+func (r *ResourceLinkArg) Reset() {
+	r.args = make(map[string]interface{})
+}
+
+// Errors returns any errors encountered when applying the specified configuration.
+func (r *ResourceLinkArg) Errors() map[string]ResponseError {
+	return r.errors
+}
+
+// Validate checks the values which have been set, returning ValidationErrors for any which the bridge would reject.
+func (r *ResourceLinkArg) Validate() error {
+	v := newValidator(r.args)
+	v.length("name", 1, maxNameLength)
+	v.length("description", 0, maxDescriptionLength)
+	v.count("links", 0, 64)
+
+	return v.err()
+}
+
+// SetName saves the specified value to be applied.
+func (r *ResourceLinkArg) SetName(name string) {
+	if r.args == nil {
+		r.args = make(map[string]interface{})
+	}
+
+	r.args["name"] = name
+}
+
+// Name returns the name option, if set.
+func (r *ResourceLinkArg) Name() string {
+	if ret, ok := r.args["name"].(string); ok {
+		return ret
+	}
+	return ""
+}
+
+// SetDescription saves the specified value to be applied.
+func (r *ResourceLinkArg) SetDescription(description string) {
+	if r.args == nil {
+		r.args = make(map[string]interface{})
+	}
+
+	r.args["description"] = description
+}
+
+// Description returns the description option, if set.
+func (r *ResourceLinkArg) Description() string {
+	if ret, ok := r.args["description"].(string); ok {
+		return ret
+	}
+	return ""
+}
+
+// SetLinks saves the specified value to be applied.
+// The supplied references replace the existing links.
+func (r *ResourceLinkArg) SetLinks(links []ResourceReference) {
+	if r.args == nil {
+		r.args = make(map[string]interface{})
+	}
+
+	r.args["links"] = links
+}
+
+// Links returns the links option, if set.
+func (r *ResourceLinkArg) Links() []ResourceReference {
+	if ret, ok := r.args["links"].([]ResourceReference); ok {
+		return ret
+	}
+	return nil
+}
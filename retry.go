@@ -0,0 +1,263 @@
+package hue
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultRetryAttempts is the number of attempts made at a request, including the first.
+	DefaultRetryAttempts = 3
+	// DefaultRetryInitialBackoff is how long to wait before the first retry.
+	DefaultRetryInitialBackoff = 100 * time.Millisecond
+	// DefaultRetryMaxBackoff is the longest to wait between attempts.
+	DefaultRetryMaxBackoff = 2 * time.Second
+)
+
+// RetryPolicy configures how requests to the bridge are retried after transient failures.
+// Network errors, 5xx responses and internal errors (type 901) reported by the bridge are transient; a response in which
+// only some of the changes failed with an internal error is returned as-is, so the other changes are not repeated.
+// Any fields which are not set use the defaults.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts made at a request, including the first.
+	MaxAttempts int
+	// InitialBackoff is how long to wait before the first retry; it doubles with each subsequent retry.
+	InitialBackoff time.Duration
+	// MaxBackoff is the longest to wait between attempts.
+	MaxBackoff time.Duration
+
+	// RetryNonIdempotent also retries POST and DELETE requests, such as CreateSensor or DeleteLight, and state changes containing increments.
+	// These aren't retried by default, as a request which failed after reaching the bridge may still have taken effect;
+	// a retried DELETE which had worked would report the resource as not available.
+	RetryNonIdempotent bool
+
+	// OnRetry, if set, is called before each retry with the number of the attempt which failed and the reason it failed.
+	OnRetry func(attempt int, err error)
+}
+
+// RetryError is returned if a request still failed after every attempt allowed by the retry policy.
+// Err is the failure of the final attempt, such as a network error or the ResponseError reported by the bridge.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return "request failed after " + strconv.Itoa(e.Attempts) + " attempts: " + e.Err.Error()
+}
+
+// Unwrap returns the error from the final attempt.
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// WithRetry retries requests to the bridge which fail for transient reasons, waiting with an exponential backoff between attempts.
+func WithRetry(policy RetryPolicy) BridgeOption {
+	if policy.MaxAttempts < 1 {
+		policy.MaxAttempts = DefaultRetryAttempts
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultRetryInitialBackoff
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultRetryMaxBackoff
+	}
+
+	return func(b *Bridge) {
+		b.retryPolicy = &policy
+	}
+}
+
+// enableRetry wraps the transport of the bridge so that failed requests are retried.
+func (b *Bridge) enableRetry() {
+	client := *b.httpClient()
+
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	client.Transport = &retryTransport{
+		next:   next,
+		policy: *b.retryPolicy,
+	}
+	b.client = &client
+}
+
+// retryTransport retries requests which can safely be repeated after a transient failure.
+type retryTransport struct {
+	next   http.RoundTripper
+	policy RetryPolicy
+}
+
+// RoundTrip performs the request, retrying it if it fails for a transient reason.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.canRetry(req) {
+		return t.next.RoundTrip(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		r := req
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+
+		resp, err := t.next.RoundTrip(r)
+
+		transientErr := transientError(req, resp, err)
+		if transientErr == nil {
+			return resp, err
+		} else if attempt >= t.policy.MaxAttempts {
+			if resp != nil {
+				closeBody(resp.Body)
+			}
+
+			return nil, &RetryError{Attempts: attempt, Err: transientErr}
+		}
+
+		if resp != nil {
+			closeBody(resp.Body)
+		}
+
+		if t.policy.OnRetry != nil {
+			t.policy.OnRetry(attempt, transientErr)
+		}
+
+		timer := time.NewTimer(t.backoff(attempt))
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// canRetry returns whether the request is safe to repeat.
+func (t *retryTransport) canRetry(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body can't be sent a second time.
+		return false
+	}
+
+	if t.policy.RetryNonIdempotent {
+		return true
+	}
+
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPut:
+		// A relative change would be applied again by each attempt.
+		return !hasIncrements(req)
+	}
+
+	return false
+}
+
+// hasIncrements returns whether the body of the request contains changes relative to the current value, such as bri_inc.
+func hasIncrements(req *http.Request) bool {
+	if req.GetBody == nil {
+		return false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	defer closeBody(body)
+
+	var values map[string]json.RawMessage
+	if err = json.NewDecoder(body).Decode(&values); err != nil {
+		return false
+	}
+
+	for key := range values {
+		if isIncrement(key) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// backoff returns how long to wait after the specified attempt, including jitter so that clients don't retry in lockstep.
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.policy.InitialBackoff
+	for i := 1; i < attempt && d < t.policy.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > t.policy.MaxBackoff {
+		d = t.policy.MaxBackoff
+	}
+
+	half := int64(d / 2)
+	return time.Duration(half + rand.Int63n(half+1))
+}
+
+// transientError returns why the result of a request is worth retrying, or nil if it succeeded or failed permanently.
+// If the response body is inspected it is replaced, so the response can still be read by the caller.
+func transientError(req *http.Request, resp *http.Response, err error) error {
+	if err != nil {
+		if req.Context().Err() != nil || errors.Is(err, ErrCertificateMismatch) || errors.Is(err, ErrNoCertificate) {
+			return nil
+		}
+
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			return err
+		}
+
+		return nil
+	}
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return errors.New("bridge returned " + resp.Status)
+	}
+
+	body, readErr := ioutil.ReadAll(resp.Body)
+	closeBody(resp.Body)
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if readErr != nil {
+		return readErr
+	}
+
+	body = bytes.TrimSpace(body)
+	if len(body) < 1 || body[0] != '[' {
+		return nil
+	}
+
+	var entries []struct {
+		Error ResponseError `json:"error"`
+	}
+	if json.Unmarshal(body, &entries) != nil {
+		return nil
+	}
+
+	// If any part of the request was applied, the response is returned so the caller learns which keys failed.
+	for _, entry := range entries {
+		if !errors.Is(entry.Error, ErrInternal) {
+			return nil
+		}
+	}
+
+	if len(entries) > 0 {
+		return entries[0].Error
+	}
+
+	return nil
+}
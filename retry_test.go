@@ -0,0 +1,200 @@
+package hue
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBridge_RetryInternalError(t *testing.T) {
+	var conns, requests int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&requests, 1) <= 2 {
+			w.Write([]byte(`[{"error":{"type":901,"address":"/lights","description":"Internal error, 404"}}]`))
+			return
+		}
+
+		w.Write([]byte(`{"1":{"name":"Hue lamp 1","state":{"on":true}}}`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	var retries []int
+	policy := RetryPolicy{
+		InitialBackoff: time.Millisecond,
+		OnRetry: func(attempt int, err error) {
+			if !errors.Is(err, ErrInternal) {
+				t.Errorf("Expected internal error to be retried, got %v\n", err)
+			}
+			retries = append(retries, attempt)
+		},
+	}
+
+	bridge := NewBridge("testuser", WithRetry(policy))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	lights, err := bridge.Lights()
+	if err != nil {
+		t.Fatalf("Unable to retrieve lights: %s\n", err.Error())
+	} else if len(lights) != 1 {
+		t.Errorf("Expected 1 light, got %d\n", len(lights))
+	}
+
+	if len(retries) != 2 || retries[0] != 1 || retries[1] != 2 {
+		t.Errorf("Expected attempts 1 and 2 to be retried, got %v\n", retries)
+	}
+}
+
+func TestBridge_RetryInternalErrorExhausted(t *testing.T) {
+	var conns, requests int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		w.Write([]byte(`[{"error":{"type":901,"address":"/lights","description":"Internal error, 404"}}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	_, err := bridge.Lights()
+
+	var retryErr *RetryError
+	if !errors.As(err, &retryErr) {
+		t.Fatalf("Expected a RetryError, got %v\n", err)
+	}
+	if retryErr.Attempts != 3 || !errors.Is(err, ErrInternal) {
+		t.Errorf("Expected the internal error after 3 attempts, got %s\n", err.Error())
+	}
+	if n := atomic.LoadInt64(&requests); n != 3 {
+		t.Errorf("Expected 3 requests, got %d\n", n)
+	}
+}
+
+func TestBridge_RetryNonIdempotent(t *testing.T) {
+	var conns, requests int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		w.Write([]byte(`[{"error":{"type":901,"address":"/sensors","description":"Internal error, 404"}}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithRetry(RetryPolicy{InitialBackoff: time.Millisecond}))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if err := bridge.CreateSensor(&Sensor{Name: "Test", Type: "CLIPGenericFlag"}); !errors.Is(err, ErrInternal) {
+		t.Errorf("Expected internal error, got %v\n", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected POST not to be retried, got %d requests\n", requests)
+	}
+
+	var args LightStateArg
+	args.SetIsOn(true)
+
+	if err := bridge.SetLightState("1", &args); !errors.Is(err, ErrInternal) {
+		t.Errorf("Expected internal error once attempts ran out, got %v\n", err)
+	}
+	if requests != 1+DefaultRetryAttempts {
+		t.Errorf("Expected PUT to be attempted %d times, got %d requests\n", DefaultRetryAttempts, requests-1)
+	}
+
+	// Each attempt at a relative change would apply it again.
+	args.Reset()
+	args.SetBrightnessIncrement(-10)
+
+	if err := bridge.SetLightState("1", &args); !errors.Is(err, ErrInternal) {
+		t.Errorf("Expected internal error, got %v\n", err)
+	}
+	if requests != 2+DefaultRetryAttempts {
+		t.Errorf("Expected PUT with an increment not to be retried, got %d requests\n", requests-1-DefaultRetryAttempts)
+	}
+}
+
+func TestBridge_RetryPartialInternalError(t *testing.T) {
+	var conns, requests int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		w.Write([]byte(`[{"success":{"/lights/1/state/on":true}},{"error":{"type":901,"address":"/lights/1/state/bri","description":"Internal error, 404"}}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithRetry(RetryPolicy{InitialBackoff: time.Millisecond}))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+	atomic.StoreInt64(&requests, 0)
+
+	var args LightStateArg
+	args.SetIsOn(true)
+	args.SetBrightness(200)
+
+	if err := bridge.SetLightState("1", &args); !errors.Is(err, ErrInternal) {
+		t.Errorf("Expected internal error, got %v\n", err)
+	}
+	if n := atomic.LoadInt64(&requests); n != 1 {
+		t.Errorf("Expected a partly applied PUT not to be retried, got %d requests\n", n)
+	}
+
+	errs := args.Errors()
+	if _, ok := errs["bri"]; !ok || len(errs) != 1 {
+		t.Errorf("Expected only the brightness to fail, got %+v\n", errs)
+	}
+	if !args.IsOn() {
+		t.Errorf("Expected the light to have been turned on\n")
+	}
+}
+
+func TestBridge_RetryDelete(t *testing.T) {
+	var conns, requests int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&requests, 1) == 1 {
+			// The light was deleted, but the response was lost.
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte(`[{"error":{"type":3,"address":"/lights/1","description":"resource, /lights/1, not available"}}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithRetry(RetryPolicy{InitialBackoff: time.Millisecond}))
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+	atomic.StoreInt64(&requests, 0)
+
+	if err := bridge.DeleteLight("1"); errors.Is(err, ErrResourceNotAvailable) {
+		t.Errorf("Expected the first failure to be reported, got %v\n", err)
+	}
+	if n := atomic.LoadInt64(&requests); n != 1 {
+		t.Errorf("Expected DELETE not to be retried, got %d requests\n", n)
+	}
+}
+
+func TestRetryTransport_Backoff(t *testing.T) {
+	rt := &retryTransport{policy: RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}}
+
+	for attempt, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+		d := rt.backoff(attempt + 1)
+		if d < max/2 || d > max {
+			t.Errorf("Expected backoff after attempt %d to be between %s and %s, got %s\n", attempt+1, max/2, max, d)
+		}
+	}
+}
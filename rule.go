@@ -0,0 +1,254 @@
+package hue
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// RuleStatusEnabled is the status of a rule which will be evaluated.
+	RuleStatusEnabled = "enabled"
+	// RuleStatusDisabled is the status of a rule which will not be evaluated.
+	RuleStatusDisabled = "disabled"
+	// RuleStatusResourceDeleted is the status of a rule which references a resource that no longer exists.
+	RuleStatusResourceDeleted = "resourcedeleted"
+)
+
+const (
+	// RuleOperatorEq matches when the attribute equals the value.
+	RuleOperatorEq = "eq"
+	// RuleOperatorGt matches when the attribute is greater than the value.
+	RuleOperatorGt = "gt"
+	// RuleOperatorLt matches when the attribute is less than the value.
+	RuleOperatorLt = "lt"
+	// RuleOperatorDx matches when the attribute has changed.
+	RuleOperatorDx = "dx"
+	// RuleOperatorDdx matches when the attribute has changed after the delay in the value.
+	RuleOperatorDdx = "ddx"
+	// RuleOperatorStable matches when the attribute has not changed for the delay in the value.
+	RuleOperatorStable = "stable"
+	// RuleOperatorNotStable matches when the attribute has changed within the delay in the value.
+	RuleOperatorNotStable = "not stable"
+	// RuleOperatorIn matches when the current time is within the time interval in the value.
+	RuleOperatorIn = "in"
+	// RuleOperatorNotIn matches when the current time is outside the time interval in the value.
+	RuleOperatorNotIn = "not in"
+)
+
+// RuleCondition is a single check which must pass for a rule to be triggered.
+type RuleCondition struct {
+	Address  string `json:"address"`
+	Operator string `json:"operator"`
+	Value    string `json:"value,omitempty"`
+}
+
+// NewSensorStateCondition creates a condition which compares the specified sensor state attribute against the value.
+// The value is ignored for operators which don't take one, such as dx.
+func NewSensorStateCondition(sensorID string, key string, operator string, value interface{}) RuleCondition {
+	c := RuleCondition{
+		Address:  "/sensors/" + sensorID + "/state/" + key,
+		Operator: operator,
+	}
+
+	if value != nil && operator != RuleOperatorDx {
+		c.Value = fmt.Sprint(value)
+	}
+
+	return c
+}
+
+// NewButtonEventConditions creates the conditions which match the specified button event being reported by a switch.
+func NewButtonEventConditions(sensorID string, buttonEvent int32) []RuleCondition {
+	return []RuleCondition{
+		NewSensorStateCondition(sensorID, "buttonevent", RuleOperatorEq, buttonEvent),
+		NewSensorStateCondition(sensorID, "lastupdated", RuleOperatorDx, nil),
+	}
+}
+
+// NewPresenceConditions creates the conditions which match a presence sensor changing to the specified value.
+func NewPresenceConditions(sensorID string, isPresent bool) []RuleCondition {
+	return []RuleCondition{
+		NewSensorStateCondition(sensorID, "presence", RuleOperatorEq, isPresent),
+		NewSensorStateCondition(sensorID, "presence", RuleOperatorDx, nil),
+	}
+}
+
+// RuleAction is a request the bridge executes when a rule is triggered.
+type RuleAction struct {
+	Address string                 `json:"address"`
+	Method  string                 `json:"method"`
+	Body    map[string]interface{} `json:"body"`
+}
+
+// NewLightStateAction creates an action which applies the supplied state to the specified light.
+func NewLightStateAction(lightID string, args *LightStateArg) RuleAction {
+	return RuleAction(NewLightStateCommand(lightID, args))
+}
+
+// NewGroupActionAction creates an action which applies the supplied state to the specified group.
+func NewGroupActionAction(groupID string, args *GroupActionArg) RuleAction {
+	return RuleAction(NewGroupActionCommand(groupID, args))
+}
+
+// NewSensorStateAction creates an action which applies the supplied state to the specified sensor.
+func NewSensorStateAction(sensorID string, args *SensorStateArg) RuleAction {
+	return RuleAction(NewSensorStateCommand(sensorID, args))
+}
+
+// Rule represents a set of actions which the bridge executes when all of the conditions are met.
+type Rule struct {
+	ID             string
+	Name           string          `json:"name"`
+	Owner          string          `json:"owner"`
+	Created        string          `json:"created"`
+	LastTriggered  string          `json:"lasttriggered"`
+	TimesTriggered int32           `json:"timestriggered"`
+	Status         string          `json:"status"`
+	Recycle        bool            `json:"recycle"`
+	Conditions     []RuleCondition `json:"conditions"`
+	Actions        []RuleAction    `json:"actions"`
+}
+
+// Rules returns the collection of rules configured on the bridge.
+func (b *Bridge) Rules() ([]Rule, error) {
+	return b.RulesWithContext(context.Background())
+}
+
+// RulesWithContext is like Rules, but the request is cancelled once the supplied context is done.
+func (b *Bridge) RulesWithContext(ctx context.Context) ([]Rule, error) {
+	if !b.isAvailable() {
+		return nil, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return nil, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/rules"
+
+	res, err := b.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(res.Body)
+
+	var respBody map[string]Rule
+
+	err = decodeResponse(res.Body, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	for id, rule := range respBody {
+		rule.ID = id
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// Rule returns a single rule from the bridge.
+func (b *Bridge) Rule(id string) (Rule, error) {
+	return b.RuleWithContext(context.Background(), id)
+}
+
+// RuleWithContext is like Rule, but the request is cancelled once the supplied context is done.
+func (b *Bridge) RuleWithContext(ctx context.Context, id string) (Rule, error) {
+	var rule Rule
+
+	if !b.isAvailable() {
+		return rule, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return rule, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/rules/" + id
+
+	resp, err := b.get(ctx, url)
+	if err != nil {
+		return rule, err
+	}
+
+	defer closeBody(resp.Body)
+
+	err = decodeResponse(resp.Body, &rule)
+	if err != nil {
+		return rule, err
+	}
+
+	rule.ID = id
+
+	return rule, nil
+}
+
+// CreateRule adds a new rule to the bridge.
+// The name, conditions, actions, status and recycle fields of the supplied rule are used; the ID is set once the rule is created.
+func (b *Bridge) CreateRule(rule *Rule) error {
+	return b.CreateRuleWithContext(context.Background(), rule)
+}
+
+// CreateRuleWithContext is like CreateRule, but the request is cancelled once the supplied context is done.
+func (b *Bridge) CreateRuleWithContext(ctx context.Context, rule *Rule) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	if err := b.validate(&RuleArg{args: map[string]interface{}{"actions": rule.Actions}}); err != nil {
+		return err
+	}
+
+	reqBody := struct {
+		Name       string          `json:"name,omitempty"`
+		Status     string          `json:"status,omitempty"`
+		Recycle    bool            `json:"recycle"`
+		Conditions []RuleCondition `json:"conditions"`
+		Actions    []RuleAction    `json:"actions"`
+	}{
+		Name:       rule.Name,
+		Status:     rule.Status,
+		Recycle:    rule.Recycle,
+		Conditions: rule.Conditions,
+		Actions:    rule.Actions,
+	}
+
+	id, err := b.create(ctx, "/rules", reqBody)
+	if err != nil {
+		return err
+	}
+
+	rule.ID = id
+	return nil
+}
+
+// SetRule updates the attributes of the specified rule.
+func (b *Bridge) SetRule(id string, args *RuleArg) error {
+	return b.SetRuleWithContext(context.Background(), id, args)
+}
+
+// SetRuleWithContext is like SetRule, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetRuleWithContext(ctx context.Context, id string, args *RuleArg) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	if err := b.validate(args); err != nil {
+		return err
+	}
+
+	return b.update(ctx, "/rules/"+id, (*arg)(args))
+}
+
+// DeleteRule removes the specified rule from the bridge.
+func (b *Bridge) DeleteRule(id string) error {
+	return b.DeleteRuleWithContext(context.Background(), id)
+}
+
+// DeleteRuleWithContext is like DeleteRule, but the request is cancelled once the supplied context is done.
+func (b *Bridge) DeleteRuleWithContext(ctx context.Context, id string) error {
+	return b.deleteResource(ctx, "/rules/"+id)
+}
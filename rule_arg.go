@@ -0,0 +1,100 @@
+package hue
+
+// RuleArg represents a configuration argument that can be made to a rule.
+type RuleArg arg
+
+// Reset clears any set configuration options.
+func (r *RuleArg) Reset() {
+	r.args = make(map[string]interface{})
+}
+
+// Errors returns any errors encountered when applying the specified configuration.
+func (r *RuleArg) Errors() map[string]ResponseError {
+	return r.errors
+}
+
+// Validate checks the values which have been set, returning ValidationErrors for any which the bridge would reject.
+// The body of each action is checked by the arg for the resource it is sent to, such as LightStateArg.
+func (r *RuleArg) Validate() error {
+	v := newValidator(r.args)
+	v.length("name", 1, maxNameLength)
+	v.count("conditions", 1, 8)
+	v.count("actions", 1, 8)
+	v.commands("actions")
+
+	return v.err()
+}
+
+// SetName saves the specified value to be applied.
+func (r *RuleArg) SetName(name string) {
+	if r.args == nil {
+		r.args = make(map[string]interface{})
+	}
+
+	r.args["name"] = name
+}
+
+// Name returns the name option, if set.
+func (r *RuleArg) Name() string {
+	if ret, ok := r.args["name"].(string); ok {
+		return ret
+	}
+	return ""
+}
+
+// SetConditions saves the specified value to be applied.
+// The supplied conditions replace the existing conditions of the rule.
+func (r *RuleArg) SetConditions(conditions []RuleCondition) {
+	if r.args == nil {
+		r.args = make(map[string]interface{})
+	}
+
+	r.args["conditions"] = conditions
+}
+
+// Conditions returns the conditions option, if set.
+func (r *RuleArg) Conditions() []RuleCondition {
+	if ret, ok := r.args["conditions"].([]RuleCondition); ok {
+		return ret
+	}
+	return nil
+}
+
+// SetActions saves the specified value to be applied.
+// The supplied actions replace the existing actions of the rule.
+func (r *RuleArg) SetActions(actions []RuleAction) {
+	if r.args == nil {
+		r.args = make(map[string]interface{})
+	}
+
+	r.args["actions"] = actions
+}
+
+// Actions returns the actions option, if set.
+func (r *RuleArg) Actions() []RuleAction {
+	if ret, ok := r.args["actions"].([]RuleAction); ok {
+		return ret
+	}
+	return nil
+}
+
+// SetIsEnabled saves the specified value to be applied.
+func (r *RuleArg) SetIsEnabled(isEnabled bool) {
+	if r.args == nil {
+		r.args = make(map[string]interface{})
+	}
+
+	if isEnabled {
+		r.args["status"] = RuleStatusEnabled
+	} else {
+		r.args["status"] = RuleStatusDisabled
+	}
+}
+
+// IsEnabled returns whether the rule is set to be enabled.
+func (r *RuleArg) IsEnabled() bool {
+	if ret, ok := r.args["status"].(string); ok {
+		return ret == RuleStatusEnabled
+	}
+	return false
+}
@@ -0,0 +1,235 @@
+package hue
+
+import (
+	"context"
+)
+
+const (
+	// SceneTypeLight is a scene which applies to an explicit list of lights.
+	SceneTypeLight = "LightScene"
+	// SceneTypeGroup is a scene which applies to the lights of a group, and is deleted along with the group.
+	SceneTypeGroup = "GroupScene"
+)
+
+// SceneAppData is an application-specific payload stored with a scene.
+type SceneAppData struct {
+	Version int    `json:"version"`
+	Data    string `json:"data"`
+}
+
+// Scene represents a stored collection of light states that can be recalled together.
+type Scene struct {
+	ID          string
+	Name        string       `json:"name"`
+	Type        string       `json:"type"`
+	Group       string       `json:"group"`
+	Lights      []string     `json:"lights"`
+	Owner       string       `json:"owner"`
+	Recycle     bool         `json:"recycle"`
+	Locked      bool         `json:"locked"`
+	AppData     SceneAppData `json:"appdata"`
+	Picture     string       `json:"picture"`
+	LastUpdated string       `json:"lastupdated"`
+	Version     int          `json:"version"`
+
+	// LightStates is only populated when a single scene is retrieved.
+	LightStates map[string]LightState `json:"lightstates"`
+}
+
+// Scenes returns the collection of scenes stored on the bridge.
+func (b *Bridge) Scenes() ([]Scene, error) {
+	return b.ScenesWithContext(context.Background())
+}
+
+// ScenesWithContext is like Scenes, but the request is cancelled once the supplied context is done.
+func (b *Bridge) ScenesWithContext(ctx context.Context) ([]Scene, error) {
+	if !b.isAvailable() {
+		return nil, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return nil, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/scenes"
+
+	res, err := b.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(res.Body)
+
+	var respBody map[string]Scene
+
+	err = decodeResponse(res.Body, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var scenes []Scene
+	for id, scene := range respBody {
+		scene.ID = id
+
+		scenes = append(scenes, scene)
+	}
+
+	return scenes, nil
+}
+
+// Scene returns a single scene, including the state stored for each of its lights.
+func (b *Bridge) Scene(id string) (Scene, error) {
+	return b.SceneWithContext(context.Background(), id)
+}
+
+// SceneWithContext is like Scene, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SceneWithContext(ctx context.Context, id string) (Scene, error) {
+	var scene Scene
+
+	if !b.isAvailable() {
+		return scene, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return scene, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/scenes/" + id
+
+	resp, err := b.get(ctx, url)
+	if err != nil {
+		return scene, err
+	}
+
+	defer closeBody(resp.Body)
+
+	err = decodeResponse(resp.Body, &scene)
+	if err != nil {
+		return scene, err
+	}
+
+	scene.ID = id
+
+	return scene, nil
+}
+
+// CreateScene adds a new scene to the bridge.
+// The name, type, group, lights, recycle, app data and picture of the supplied scene are used; the ID is set once the scene is created.
+// If no light states are supplied the bridge captures the current state of each light in the scene.
+func (b *Bridge) CreateScene(scene *Scene, lightStates map[string]*LightStateArg) error {
+	return b.CreateSceneWithContext(context.Background(), scene, lightStates)
+}
+
+// CreateSceneWithContext is like CreateScene, but the request is cancelled once the supplied context is done.
+func (b *Bridge) CreateSceneWithContext(ctx context.Context, scene *Scene, lightStates map[string]*LightStateArg) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	for _, lightState := range lightStates {
+		if err := b.validate(lightState); err != nil {
+			return err
+		}
+	}
+
+	reqBody := struct {
+		Name        string                            `json:"name"`
+		Type        string                            `json:"type,omitempty"`
+		Group       string                            `json:"group,omitempty"`
+		Lights      []string                          `json:"lights,omitempty"`
+		Recycle     bool                              `json:"recycle"`
+		AppData     *SceneAppData                     `json:"appdata,omitempty"`
+		Picture     string                            `json:"picture,omitempty"`
+		LightStates map[string]map[string]interface{} `json:"lightstates,omitempty"`
+	}{
+		Name:    scene.Name,
+		Type:    scene.Type,
+		Group:   scene.Group,
+		Lights:  scene.Lights,
+		Recycle: scene.Recycle,
+		Picture: scene.Picture,
+	}
+
+	if scene.AppData.Version > 0 || len(scene.AppData.Data) > 0 {
+		reqBody.AppData = &scene.AppData
+	}
+
+	if len(lightStates) > 0 {
+		reqBody.LightStates = make(map[string]map[string]interface{})
+
+		for lightID, lightState := range lightStates {
+			reqBody.LightStates[lightID] = lightState.args
+		}
+	}
+
+	id, err := b.create(ctx, "/scenes", reqBody)
+	if err != nil {
+		return err
+	}
+
+	scene.ID = id
+	return nil
+}
+
+// SetScene updates the attributes of the specified scene.
+// Setting the store light state option captures the current state of the lights in the scene.
+func (b *Bridge) SetScene(id string, args *SceneArg) error {
+	return b.SetSceneWithContext(context.Background(), id, args)
+}
+
+// SetSceneWithContext is like SetScene, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetSceneWithContext(ctx context.Context, id string, args *SceneArg) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	if err := b.validate(args); err != nil {
+		return err
+	}
+
+	return b.update(ctx, "/scenes/"+id, (*arg)(args))
+}
+
+// SetSceneLightState updates the state stored in the specified scene for a single light.
+func (b *Bridge) SetSceneLightState(id string, lightID string, args *LightStateArg) error {
+	return b.SetSceneLightStateWithContext(context.Background(), id, lightID, args)
+}
+
+// SetSceneLightStateWithContext is like SetSceneLightState, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetSceneLightStateWithContext(ctx context.Context, id string, lightID string, args *LightStateArg) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	if err := b.validate(args); err != nil {
+		return err
+	}
+
+	return b.update(ctx, "/scenes/"+id+"/lightstates/"+lightID, (*arg)(args))
+}
+
+// DeleteScene removes the specified scene from the bridge.
+func (b *Bridge) DeleteScene(id string) error {
+	return b.DeleteSceneWithContext(context.Background(), id)
+}
+
+// DeleteSceneWithContext is like DeleteScene, but the request is cancelled once the supplied context is done.
+func (b *Bridge) DeleteSceneWithContext(ctx context.Context, id string) error {
+	return b.deleteResource(ctx, "/scenes/"+id)
+}
+
+// RecallScene applies the states stored in the specified scene to the lights of the specified group.
+// Only the lights which are in both the group and the scene are changed; group "0" can be used to target every light in the scene.
+func (b *Bridge) RecallScene(groupID string, sceneID string) error {
+	return b.RecallSceneWithContext(context.Background(), groupID, sceneID)
+}
+
+// RecallSceneWithContext is like RecallScene, but the request is cancelled once the supplied context is done.
+func (b *Bridge) RecallSceneWithContext(ctx context.Context, groupID string, sceneID string) error {
+	var args GroupActionArg
+	args.SetScene(sceneID)
+
+	return b.SetGroupActionWithContext(ctx, groupID, &args)
+}
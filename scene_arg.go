@@ -0,0 +1,93 @@
+package hue
+
+// SceneArg represents a configuration argument that can be made to a scene.
+type SceneArg arg
+
+// Reset clears any set configuration options.
+func (s *SceneArg) Reset() {
+	s.args = make(map[string]interface{})
+}
+
+// Errors returns any errors encountered when applying the specified configuration.
+func (s *SceneArg) Errors() map[string]ResponseError {
+	return s.errors
+}
+
+// Validate checks the values which have been set, returning ValidationErrors for any which the bridge would reject.
+func (s *SceneArg) Validate() error {
+	v := newValidator(s.args)
+	v.length("name", 1, maxNameLength)
+
+	return v.err()
+}
+
+// SetName saves the specified value to be applied.
+func (s *SceneArg) SetName(name string) {
+	if s.args == nil {
+		s.args = make(map[string]interface{})
+	}
+
+	s.args["name"] = name
+}
+
+// Name returns the name option, if set.
+func (s *SceneArg) Name() string {
+	if ret, ok := s.args["name"].(string); ok {
+		return ret
+	}
+	return ""
+}
+
+// SetLights saves the specified value to be applied.
+// This is only valid for scenes of type LightScene.
+func (s *SceneArg) SetLights(lights []string) {
+	if s.args == nil {
+		s.args = make(map[string]interface{})
+	}
+
+	s.args["lights"] = lights
+}
+
+// Lights returns the lights option, if set.
+func (s *SceneArg) Lights() []string {
+	if ret, ok := s.args["lights"].([]string); ok {
+		return ret
+	}
+	return nil
+}
+
+// SetStoreLightState saves the specified value to be applied.
+// When set, the current state of each light in the scene is captured into the scene.
+func (s *SceneArg) SetStoreLightState(store bool) {
+	if s.args == nil {
+		s.args = make(map[string]interface{})
+	}
+
+	s.args["storelightstate"] = store
+}
+
+// StoreLightState returns the store light state option, if set.
+func (s *SceneArg) StoreLightState() bool {
+	if ret, ok := s.args["storelightstate"].(bool); ok {
+		return ret
+	}
+	return false
+}
+
+// SetTransitionTime saves the specified value to be applied to each captured light state.
+// This is only used when the store light state option is also set.
+func (s *SceneArg) SetTransitionTime(tt uint16) {
+	if s.args == nil {
+		s.args = make(map[string]interface{})
+	}
+
+	s.args["transitiontime"] = tt
+}
+
+// TransitionTime returns the transition time, if configured.
+func (s *SceneArg) TransitionTime() uint16 {
+	if ret, ok := s.args["transitiontime"].(uint16); ok {
+		return ret
+	}
+	return 0
+}
@@ -0,0 +1,280 @@
+package hue
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// ScheduleStatusEnabled is the status of a schedule which will fire.
+	ScheduleStatusEnabled = "enabled"
+	// ScheduleStatusDisabled is the status of a schedule which will not fire.
+	ScheduleStatusDisabled = "disabled"
+)
+
+// ScheduleCommand is the request the bridge executes when a schedule fires.
+type ScheduleCommand struct {
+	Address string                 `json:"address"`
+	Method  string                 `json:"method"`
+	Body    map[string]interface{} `json:"body"`
+}
+
+// NewLightStateCommand creates a command which applies the supplied state to the specified light.
+func NewLightStateCommand(lightID string, args *LightStateArg) ScheduleCommand {
+	return ScheduleCommand{
+		Address: "/lights/" + lightID + "/state",
+		Method:  http.MethodPut,
+		Body:    args.args,
+	}
+}
+
+// NewGroupActionCommand creates a command which applies the supplied state to the specified group.
+func NewGroupActionCommand(groupID string, args *GroupActionArg) ScheduleCommand {
+	return ScheduleCommand{
+		Address: "/groups/" + groupID + "/action",
+		Method:  http.MethodPut,
+		Body:    args.args,
+	}
+}
+
+// NewSensorStateCommand creates a command which applies the supplied state to the specified sensor.
+func NewSensorStateCommand(sensorID string, args *SensorStateArg) ScheduleCommand {
+	return ScheduleCommand{
+		Address: "/sensors/" + sensorID + "/state",
+		Method:  http.MethodPut,
+		Body:    args.args,
+	}
+}
+
+// Schedule represents a command which the bridge executes at a specified time.
+type Schedule struct {
+	ID          string
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Command     ScheduleCommand `json:"command"`
+	LocalTime   TimePattern     `json:"localtime"`
+	Created     string          `json:"created"`
+	Status      string          `json:"status"`
+	AutoDelete  bool            `json:"autodelete"`
+	StartTime   string          `json:"starttime"`
+	Recycle     bool            `json:"recycle"`
+}
+
+// TimePatternErrors is returned along with the schedules if the local time of any of them couldn't be parsed.
+// It is keyed by the ID of each schedule, holding the local time reported by the bridge, which is also kept in the Raw
+// field of the schedule's LocalTime.
+type TimePatternErrors map[string]string
+
+// Error returns the local time of each schedule which couldn't be parsed.
+func (e TimePatternErrors) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	msgs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		msgs = append(msgs, "schedule "+id+": "+ErrInvalidTimePattern.Error()+" "+strconv.Quote(e[id]))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Is allows the errors to be matched against ErrInvalidTimePattern.
+func (e TimePatternErrors) Is(target error) bool {
+	return target == ErrInvalidTimePattern
+}
+
+// checkLocalTime adds the local time of the schedule to the errors if it couldn't be parsed.
+func checkLocalTime(errs TimePatternErrors, schedule Schedule) TimePatternErrors {
+	if len(schedule.LocalTime.Raw) < 1 {
+		return errs
+	}
+
+	if errs == nil {
+		errs = make(TimePatternErrors)
+	}
+
+	errs[schedule.ID] = schedule.LocalTime.Raw
+	return errs
+}
+
+// commandAddress returns the full resource address the bridge expects in a schedule command.
+// Addresses created by the New*Command helpers are relative to the API user, so they are prefixed here.
+func (b *Bridge) commandAddress(address string) string {
+	if strings.HasPrefix(address, "/api/") {
+		return address
+	}
+
+	return "/api/" + b.username() + address
+}
+
+// Schedules returns the collection of schedules configured on the bridge.
+// If the local time of any schedule couldn't be parsed, every schedule is still returned along with TimePatternErrors.
+func (b *Bridge) Schedules() ([]Schedule, error) {
+	return b.SchedulesWithContext(context.Background())
+}
+
+// SchedulesWithContext is like Schedules, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SchedulesWithContext(ctx context.Context) ([]Schedule, error) {
+	if !b.isAvailable() {
+		return nil, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return nil, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/schedules"
+
+	res, err := b.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	defer closeBody(res.Body)
+
+	var respBody map[string]Schedule
+
+	err = decodeResponse(res.Body, &respBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedules []Schedule
+	var errs TimePatternErrors
+	for id, schedule := range respBody {
+		schedule.ID = id
+
+		schedules = append(schedules, schedule)
+		errs = checkLocalTime(errs, schedule)
+	}
+
+	if errs != nil {
+		return schedules, errs
+	}
+
+	return schedules, nil
+}
+
+// Schedule returns a single schedule from the bridge.
+// If the local time of the schedule couldn't be parsed, the schedule is still returned along with TimePatternErrors.
+func (b *Bridge) Schedule(id string) (Schedule, error) {
+	return b.ScheduleWithContext(context.Background(), id)
+}
+
+// ScheduleWithContext is like Schedule, but the request is cancelled once the supplied context is done.
+func (b *Bridge) ScheduleWithContext(ctx context.Context, id string) (Schedule, error) {
+	var schedule Schedule
+
+	if !b.isAvailable() {
+		return schedule, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return schedule, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/schedules/" + id
+
+	resp, err := b.get(ctx, url)
+	if err != nil {
+		return schedule, err
+	}
+
+	defer closeBody(resp.Body)
+
+	err = decodeResponse(resp.Body, &schedule)
+	if err != nil {
+		return schedule, err
+	}
+
+	schedule.ID = id
+
+	if errs := checkLocalTime(nil, schedule); errs != nil {
+		return schedule, errs
+	}
+
+	return schedule, nil
+}
+
+// CreateSchedule adds a new schedule to the bridge.
+// The name, description, command, local time, status and recycle fields of the supplied schedule are used; the ID is set once the schedule is created.
+// The auto delete field isn't sent, so the bridge deletes a schedule which has run unless SetSchedule is used to disable it.
+func (b *Bridge) CreateSchedule(schedule *Schedule) error {
+	return b.CreateScheduleWithContext(context.Background(), schedule)
+}
+
+// CreateScheduleWithContext is like CreateSchedule, but the request is cancelled once the supplied context is done.
+func (b *Bridge) CreateScheduleWithContext(ctx context.Context, schedule *Schedule) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	if err := b.validate(&ScheduleArg{args: map[string]interface{}{"command": schedule.Command}}); err != nil {
+		return err
+	}
+
+	reqBody := struct {
+		Name        string          `json:"name,omitempty"`
+		Description string          `json:"description,omitempty"`
+		Command     ScheduleCommand `json:"command"`
+		LocalTime   TimePattern     `json:"localtime"`
+		Status      string          `json:"status,omitempty"`
+		Recycle     bool            `json:"recycle"`
+	}{
+		Name:        schedule.Name,
+		Description: schedule.Description,
+		Command:     schedule.Command,
+		LocalTime:   schedule.LocalTime,
+		Status:      schedule.Status,
+		Recycle:     schedule.Recycle,
+	}
+
+	reqBody.Command.Address = b.commandAddress(reqBody.Command.Address)
+
+	id, err := b.create(ctx, "/schedules", reqBody)
+	if err != nil {
+		return err
+	}
+
+	schedule.ID = id
+	return nil
+}
+
+// SetSchedule updates the attributes of the specified schedule.
+func (b *Bridge) SetSchedule(id string, args *ScheduleArg) error {
+	return b.SetScheduleWithContext(context.Background(), id, args)
+}
+
+// SetScheduleWithContext is like SetSchedule, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetScheduleWithContext(ctx context.Context, id string, args *ScheduleArg) error {
+	if !b.isAvailable() {
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	}
+
+	if err := b.validate(args); err != nil {
+		return err
+	}
+
+	if cmd, ok := args.args["command"].(ScheduleCommand); ok {
+		cmd.Address = b.commandAddress(cmd.Address)
+		args.args["command"] = cmd
+	}
+
+	return b.update(ctx, "/schedules/"+id, (*arg)(args))
+}
+
+// DeleteSchedule removes the specified schedule from the bridge.
+func (b *Bridge) DeleteSchedule(id string) error {
+	return b.DeleteScheduleWithContext(context.Background(), id)
+}
+
+// DeleteScheduleWithContext is like DeleteSchedule, but the request is cancelled once the supplied context is done.
+func (b *Bridge) DeleteScheduleWithContext(ctx context.Context, id string) error {
+	return b.deleteResource(ctx, "/schedules/"+id)
+}
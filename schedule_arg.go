@@ -0,0 +1,131 @@
+package hue
+
+// ScheduleArg represents a configuration argument that can be made to a schedule.
+type ScheduleArg arg
+
+// Reset clears any set configuration options.
+func (s *ScheduleArg) Reset() {
+	s.args = make(map[string]interface{})
+}
+
+// Errors returns any errors encountered when applying the specified configuration.
+func (s *ScheduleArg) Errors() map[string]ResponseError {
+	return s.errors
+}
+
+// Validate checks the values which have been set, returning ValidationErrors for any which the bridge would reject.
+// The body of the command is checked by the arg for the resource it is sent to, such as LightStateArg.
+func (s *ScheduleArg) Validate() error {
+	v := newValidator(s.args)
+	v.length("name", 0, maxNameLength)
+	v.length("description", 0, maxDescriptionLength)
+	v.commands("command")
+
+	return v.err()
+}
+
+// SetName saves the specified value to be applied.
+func (s *ScheduleArg) SetName(name string) {
+	if s.args == nil {
+		s.args = make(map[string]interface{})
+	}
+
+	s.args["name"] = name
+}
+
+// Name returns the name option, if set.
+func (s *ScheduleArg) Name() string {
+	if ret, ok := s.args["name"].(string); ok {
+		return ret
+	}
+	return ""
+}
+
+// SetDescription saves the specified value to be applied.
+func (s *ScheduleArg) SetDescription(description string) {
+	if s.args == nil {
+		s.args = make(map[string]interface{})
+	}
+
+	s.args["description"] = description
+}
+
+// Description returns the description option, if set.
+func (s *ScheduleArg) Description() string {
+	if ret, ok := s.args["description"].(string); ok {
+		return ret
+	}
+	return ""
+}
+
+// SetCommand saves the specified value to be applied.
+func (s *ScheduleArg) SetCommand(command ScheduleCommand) {
+	if s.args == nil {
+		s.args = make(map[string]interface{})
+	}
+
+	s.args["command"] = command
+}
+
+// Command returns the command option, if set.
+func (s *ScheduleArg) Command() ScheduleCommand {
+	if ret, ok := s.args["command"].(ScheduleCommand); ok {
+		return ret
+	}
+	return ScheduleCommand{}
+}
+
+// SetLocalTime saves the specified value to be applied.
+func (s *ScheduleArg) SetLocalTime(localTime TimePattern) {
+	if s.args == nil {
+		s.args = make(map[string]interface{})
+	}
+
+	s.args["localtime"] = localTime
+}
+
+// LocalTime returns the local time option, if set.
+func (s *ScheduleArg) LocalTime() TimePattern {
+	if ret, ok := s.args["localtime"].(TimePattern); ok {
+		return ret
+	}
+	return TimePattern{}
+}
+
+// SetIsEnabled saves the specified value to be applied.
+func (s *ScheduleArg) SetIsEnabled(isEnabled bool) {
+	if s.args == nil {
+		s.args = make(map[string]interface{})
+	}
+
+	if isEnabled {
+		s.args["status"] = ScheduleStatusEnabled
+	} else {
+		s.args["status"] = ScheduleStatusDisabled
+	}
+}
+
+// IsEnabled returns whether the schedule is set to be enabled.
+func (s *ScheduleArg) IsEnabled() bool {
+	if ret, ok := s.args["status"].(string); ok {
+		return ret == ScheduleStatusEnabled
+	}
+	return false
+}
+
+// SetAutoDelete saves the specified value to be applied.
+func (s *ScheduleArg) SetAutoDelete(autoDelete bool) {
+	if s.args == nil {
+		s.args = make(map[string]interface{})
+	}
+
+	s.args["autodelete"] = autoDelete
+}
+
+// AutoDelete returns the auto delete option, if set.
+func (s *ScheduleArg) AutoDelete() bool {
+	if ret, ok := s.args["autodelete"].(bool); ok {
+		return ret
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package hue
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestBridge_ScheduleAutoDelete(t *testing.T) {
+	var body map[string]interface{}
+
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body = nil
+		json.Unmarshal(data, &body)
+
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`[{"success":{"id":"1"}}]`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	schedule := &Schedule{
+		Command:   NewLightStateCommand("1", &LightStateArg{}),
+		LocalTime: NewTimer(10 * time.Minute),
+	}
+	if err := bridge.CreateSchedule(schedule); err != nil {
+		t.Fatalf("Unable to create schedule: %s\n", err.Error())
+	}
+	if _, ok := body["autodelete"]; ok {
+		t.Errorf("Expected auto delete not to be sent when creating a schedule, got %v\n", body["autodelete"])
+	}
+
+	var args ScheduleArg
+	args.SetName("Wake up")
+	if err := bridge.SetSchedule(schedule.ID, &args); err != nil {
+		t.Fatalf("Unable to set schedule: %s\n", err.Error())
+	}
+	if _, ok := body["autodelete"]; ok {
+		t.Errorf("Expected auto delete not to be sent unless set, got %v\n", body["autodelete"])
+	}
+
+	args.SetAutoDelete(false)
+	if err := bridge.SetSchedule(schedule.ID, &args); err != nil {
+		t.Fatalf("Unable to set schedule: %s\n", err.Error())
+	}
+	if body["autodelete"] != false {
+		t.Errorf("Expected auto delete to be disabled, got %v\n", body["autodelete"])
+	}
+}
+
+func TestBridge_SchedulesInvalidTimePattern(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"1":{"name":"Timer","localtime":"PT00:10:00"},
+			"2":{"name":"Sunset","localtime":"sunset+00:30"}
+		}`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	schedules, err := bridge.Schedules()
+	if !errors.Is(err, ErrInvalidTimePattern) {
+		t.Fatalf("Expected an invalid time pattern, got %v\n", err)
+	}
+
+	var errs TimePatternErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs["2"] != "sunset+00:30" {
+		t.Errorf("Expected only the second schedule to be reported, got %v\n", err)
+	}
+
+	if len(schedules) != 2 {
+		t.Fatalf("Expected both schedules to be returned, got %d\n", len(schedules))
+	}
+	for _, schedule := range schedules {
+		if schedule.ID == "1" && schedule.LocalTime.Duration != 10*time.Minute {
+			t.Errorf("Expected the valid local time to be parsed, got %+v\n", schedule.LocalTime)
+		} else if schedule.ID == "2" && schedule.LocalTime.String() != "sunset+00:30" {
+			t.Errorf("Expected the invalid local time to be kept, got %+v\n", schedule.LocalTime)
+		}
+	}
+}
@@ -1,12 +1,11 @@
 package hue
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/url"
-	"strings"
+	"time"
 )
 
 // SensorState represents the state of a sensor.
@@ -78,46 +77,82 @@ type NewSensor struct {
 	Name string `json:"name"`
 }
 
+// sensorScanPollInterval is how often PairSensors checks whether the search for new sensors has completed.
+// It is a variable so tests don't have to wait as long as a real search takes.
+var sensorScanPollInterval = 5 * time.Second
+
 // SearchForNewSensors attempts to discover new sensors added to the bridge.
-func (b *Bridge) SearchForNewSensors() error {
-	return errors.New("search is not yet supported")
+// The search runs for roughly a minute; NewSensors reports the results and whether the search is still active.
+// Up to 10 Zigbee device IDs may be supplied to locate sensors which were already paired with another bridge.
+func (b *Bridge) SearchForNewSensors(deviceIDs ...string) error {
+	return b.SearchForNewSensorsWithContext(context.Background(), deviceIDs...)
 }
 
-// NewSensors returns the list of newly configured sensors.
-// This will not return anything if SearchForNewSensors has not previously been called.
-func (b *Bridge) NewSensors() ([]NewSensor, error) {
+// SearchForNewSensorsWithContext is like SearchForNewSensors, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SearchForNewSensorsWithContext(ctx context.Context, deviceIDs ...string) error {
 	if !b.isAvailable() {
-		return nil, ErrBridgeNotAvailable
-	} else if b.updateInProgress {
-		return nil, ErrBridgeUpdating
+		return ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return ErrBridgeUpdating
+	} else if len(deviceIDs) > maxSearchDeviceIDs {
+		return ErrTooManyDeviceIDs
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/sensors/new"
+	reqBody := struct {
+		DeviceIDs []string `json:"deviceid,omitempty"`
+	}{
+		DeviceIDs: deviceIDs,
+	}
+
+	return b.do(ctx, http.MethodPost, "/sensors", reqBody)
+}
+
+// NewSensors returns the list of sensors discovered by the most recent search, along with the status of that search.
+// This will not return any sensors if SearchForNewSensors has not previously been called.
+func (b *Bridge) NewSensors() ([]NewSensor, ScanStatus, error) {
+	return b.NewSensorsWithContext(context.Background())
+}
+
+// NewSensorsWithContext is like NewSensors, but the request is cancelled once the supplied context is done.
+func (b *Bridge) NewSensorsWithContext(ctx context.Context) ([]NewSensor, ScanStatus, error) {
+	var status ScanStatus
 
-	resp, err := http.Get(url)
+	if !b.isAvailable() {
+		return nil, status, ErrBridgeNotAvailable
+	} else if b.IsUpdating() {
+		return nil, status, ErrBridgeUpdating
+	}
+
+	url := b.apiURL() + "/sensors/new"
+
+	resp, err := b.get(ctx, url)
 	if err != nil {
-		return nil, err
+		return nil, status, err
 	}
 
+	defer closeBody(resp.Body)
+
 	var respEntries map[string]*json.RawMessage
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
+	err = decodeResponse(resp.Body, &respEntries)
 	if err != nil {
-		return nil, err
+		return nil, status, err
 	}
 
 	var sensors []NewSensor
 	for key, respEntry := range respEntries {
 		if key == "lastscan" {
-			var time string
-			if err = json.Unmarshal(*respEntry, &time); err != nil {
-				return nil, err
+			var lastScan string
+			if err = json.Unmarshal(*respEntry, &lastScan); err != nil {
+				return nil, status, err
 			}
 
-			// TODO: do something with this value?
+			if status, err = parseScanStatus(lastScan); err != nil {
+				return nil, status, err
+			}
 		} else {
 			var s NewSensor
 			if err = json.Unmarshal(*respEntry, &s); err != nil {
-				return nil, err
+				return nil, status, err
 			}
 
 			s.ID = key
@@ -126,26 +161,72 @@ func (b *Bridge) NewSensors() ([]NewSensor, error) {
 		}
 	}
 
-	return sensors, nil
+	return sensors, status, nil
+}
+
+// PairSensors searches for new sensors, then blocks until the search completes or the context is done.
+// The complete details of each sensor which joined the bridge during the search are returned.
+func (b *Bridge) PairSensors(ctx context.Context) ([]Sensor, error) {
+	err := b.SearchForNewSensorsWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(sensorScanPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		newSensors, status, err := b.NewSensorsWithContext(ctx)
+		if err != nil {
+			return nil, err
+		} else if status.Active {
+			continue
+		}
+
+		var sensors []Sensor
+		for _, newSensor := range newSensors {
+			sensor, err := b.SensorWithContext(ctx, newSensor.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			sensors = append(sensors, sensor)
+		}
+
+		return sensors, nil
+	}
 }
 
 // Sensors returns the list of sensors available on the bridge.
 func (b *Bridge) Sensors() ([]Sensor, error) {
+	return b.SensorsWithContext(context.Background())
+}
+
+// SensorsWithContext is like Sensors, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SensorsWithContext(ctx context.Context) ([]Sensor, error) {
 	if !b.isAvailable() {
 		return nil, ErrBridgeNotAvailable
-	} else if b.updateInProgress {
+	} else if b.IsUpdating() {
 		return nil, ErrBridgeUpdating
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/sensors"
+	url := b.apiURL() + "/sensors"
 
-	res, err := http.Get(url)
+	res, err := b.get(ctx, url)
 	if err != nil {
 		return nil, err
 	}
 
+	defer closeBody(res.Body)
+
 	var respBody map[string]Sensor
-	err = json.NewDecoder(res.Body).Decode(&respBody)
+	err = decodeResponse(res.Body, &respBody)
 	if err != nil {
 		return nil, err
 	}
@@ -162,347 +243,126 @@ func (b *Bridge) Sensors() ([]Sensor, error) {
 
 // Sensor returns the specified sensor.
 func (b *Bridge) Sensor(id string) (Sensor, error) {
+	return b.SensorWithContext(context.Background(), id)
+}
+
+// SensorWithContext is like Sensor, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SensorWithContext(ctx context.Context, id string) (Sensor, error) {
 	var sensor Sensor
 
 	if !b.isAvailable() {
 		return sensor, ErrBridgeNotAvailable
-	} else if b.updateInProgress {
+	} else if b.IsUpdating() {
 		return sensor, ErrBridgeUpdating
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/sensors/" + id
+	url := b.apiURL() + "/sensors/" + id
 
-	resp, err := http.Get(url)
+	resp, err := b.get(ctx, url)
 	if err != nil {
 		return sensor, err
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&sensor)
-	return sensor, err
+	defer closeBody(resp.Body)
+
+	err = decodeResponse(resp.Body, &sensor)
+	if err != nil {
+		return sensor, err
+	}
+
+	sensor.ID = id
+
+	return sensor, nil
 }
 
 // SetSensor updates the configuration of the specified sensor.
 func (b *Bridge) SetSensor(id string, args *SensorArg) error {
+	return b.SetSensorWithContext(context.Background(), id, args)
+}
+
+// SetSensorWithContext is like SetSensor, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetSensorWithContext(ctx context.Context, id string, args *SensorArg) error {
 	if !b.isAvailable() {
 		return ErrBridgeNotAvailable
-	} else if b.updateInProgress {
+	} else if b.IsUpdating() {
 		return ErrBridgeUpdating
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/sensors/" + id
-
-	buf := new(bytes.Buffer)
-	err := json.NewEncoder(buf).Encode(args.args)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPut, url, buf)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	var respEntries responseEntries
-
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
-	if err != nil {
+	if err := b.validate(args); err != nil {
 		return err
 	}
 
-	for _, respEntry := range respEntries {
-		var e responseEntry
-		if err = json.Unmarshal(respEntry, &e); err != nil {
-			return err
-		}
-
-		if e.Error.Type > 0 {
-			if args.errors == nil {
-				args.errors = make(map[string]ResponseError)
-			}
-
-			keys := strings.Split(e.Error.Address, "/")
-			key := keys[len(keys)-1]
-
-			args.errors[key] = e.Error
-		} else {
-			for path, jsonValue := range e.Success {
-				keys := strings.Split(path, "/")
-
-				key := keys[len(keys)-1]
-
-				if key == "name" {
-					var v string
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				}
-			}
-		}
-	}
-
-	return nil
+	return b.update(ctx, "/sensors/"+id, (*arg)(args))
 }
 
 // SetSensorConfig updates the configuration a sensor.
 func (b *Bridge) SetSensorConfig(id string, args *SensorConfigArg) error {
+	return b.SetSensorConfigWithContext(context.Background(), id, args)
+}
+
+// SetSensorConfigWithContext is like SetSensorConfig, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetSensorConfigWithContext(ctx context.Context, id string, args *SensorConfigArg) error {
 	if !b.isAvailable() {
 		return ErrBridgeNotAvailable
-	} else if b.updateInProgress {
+	} else if b.IsUpdating() {
 		return ErrBridgeUpdating
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/sensors/" + id + "/config"
-
-	buf := new(bytes.Buffer)
-	err := json.NewEncoder(buf).Encode(args.args)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPut, url, buf)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
-	if err != nil {
+	if err := b.validate(args); err != nil {
 		return err
 	}
 
-	var respEntries responseEntries
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
-	if err != nil {
-		return err
-	}
-
-	for _, respEntry := range respEntries {
-		var e responseEntry
-		if err = json.Unmarshal(respEntry, &e); err != nil {
-			return err
-		}
-
-		if e.Error.Type > 0 {
-			if args.errors == nil {
-				args.errors = make(map[string]ResponseError)
-			}
-
-			keys := strings.Split(e.Error.Address, "/")
-			key := keys[len(keys)-1]
-
-			args.errors[key] = e.Error
-		} else {
-			for path, jsonValue := range e.Success {
-				keys := strings.Split(path, "/")
-
-				key := keys[len(keys)-1]
-
-				if key == "alert" || key == "url" || key == "lat" || key == "long" {
-					var v string
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				} else if key == "on" || key == "reachable" {
-					var v bool
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				} else if key == "battery" {
-					var v uint8
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				} else if key == "sunriseoffset" || key == "sunsetoffset" {
-					var v int8
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				} else if key == "tholddark" || key == "tholdoffset" {
-					var v uint16
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				}
-			}
-		}
-	}
-
-	return nil
+	return b.update(ctx, "/sensors/"+id+"/config", (*arg)(args))
 }
 
 // SetSensorState updates the state of the specified sensor.
 func (b *Bridge) SetSensorState(id string, args *SensorStateArg) error {
+	return b.SetSensorStateWithContext(context.Background(), id, args)
+}
+
+// SetSensorStateWithContext is like SetSensorState, but the request is cancelled once the supplied context is done.
+func (b *Bridge) SetSensorStateWithContext(ctx context.Context, id string, args *SensorStateArg) error {
 	if !b.isAvailable() {
 		return ErrBridgeNotAvailable
-	} else if b.updateInProgress {
+	} else if b.IsUpdating() {
 		return ErrBridgeUpdating
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/sensors/" + id + "/state"
-
-	buf := new(bytes.Buffer)
-	err := json.NewEncoder(buf).Encode(args.args)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPut, url, buf)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	var respEntries responseEntries
-
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
-	if err != nil {
+	if err := b.validate(args); err != nil {
 		return err
 	}
 
-	for _, respEntry := range respEntries {
-		var e responseEntry
-		if err = json.Unmarshal(respEntry, &e); err != nil {
-			return err
-		}
-
-		if e.Error.Type > 0 {
-			if args.errors == nil {
-				args.errors = make(map[string]ResponseError)
-			}
-
-			keys := strings.Split(e.Error.Address, "/")
-			key := keys[len(keys)-1]
-
-			args.errors[key] = e.Error
-		} else {
-			for path, jsonValue := range e.Success {
-				keys := strings.Split(path, "/")
-
-				key := keys[len(keys)-1]
-
-				if key == "open" || key == "presence" || key == "flag" {
-					var v bool
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				} else if key == "temperature" || key == "humidity" || key == "status" {
-					var v int32
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				} else if key == "lightlevel" {
-					var v uint16
-					if err = json.Unmarshal(*jsonValue, &v); err != nil {
-						return err
-					}
-
-					args.args[key] = v
-				}
-			}
-		}
-	}
-
-	return nil
+	return b.update(ctx, "/sensors/"+id+"/state", (*arg)(args))
 }
 
 // CreateSensor adds a new sensor to the bridge.
 func (b *Bridge) CreateSensor(sensor *Sensor) error {
+	return b.CreateSensorWithContext(context.Background(), sensor)
+}
+
+// CreateSensorWithContext is like CreateSensor, but the request is cancelled once the supplied context is done.
+func (b *Bridge) CreateSensorWithContext(ctx context.Context, sensor *Sensor) error {
 	if !b.isAvailable() {
 		return ErrBridgeNotAvailable
-	} else if b.updateInProgress {
+	} else if b.IsUpdating() {
 		return ErrBridgeUpdating
 	}
 
-	url := b.baseURL.String() + "api/" + b.Username + "/sensors"
-
-	buf := new(bytes.Buffer)
-	err := json.NewEncoder(buf).Encode(sensor)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, url, buf)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	client := &http.Client{}
-
-	resp, err := client.Do(req)
+	id, err := b.create(ctx, "/sensors", sensor)
 	if err != nil {
 		return err
 	}
 
-	var respEntries responseEntries
-	err = json.NewDecoder(resp.Body).Decode(&respEntries)
-	if err != nil {
-		return err
-	}
-
-	for _, respEntry := range respEntries {
-		var e responseEntry
-		if err = json.Unmarshal(respEntry, &e); err != nil {
-			return err
-		}
-
-		if e.Error.Type > 0 {
-			return errors.New(e.Error.Description)
-		}
-
-		for path, jsonValue := range e.Success {
-			keys := strings.Split(path, "/")
-
-			key := keys[len(keys)-1]
-
-			if key == "id" {
-				var v string
-				if err = json.Unmarshal(*jsonValue, &v); err != nil {
-					return err
-				}
-
-				sensor.ID = v
-			}
-		}
-	}
-
+	sensor.ID = id
 	return nil
 }
 
 // DeleteSensor removes the specified sensor from the bridge.
 func (b *Bridge) DeleteSensor(id string) error {
-	return errors.New("delete is not yet supported")
+	return b.DeleteSensorWithContext(context.Background(), id)
+}
+
+// DeleteSensorWithContext is like DeleteSensor, but the request is cancelled once the supplied context is done.
+func (b *Bridge) DeleteSensorWithContext(ctx context.Context, id string) error {
+	return b.deleteResource(ctx, "/sensors/"+id)
 }
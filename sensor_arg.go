@@ -13,6 +13,14 @@ func (s *SensorArg) Errors() map[string]ResponseError {
 	return s.errors
 }
 
+// Validate checks the values which have been set, returning ValidationErrors for any which the bridge would reject.
+func (s *SensorArg) Validate() error {
+	v := newValidator(s.args)
+	v.length("name", 1, maxNameLength)
+
+	return v.err()
+}
+
 // SetName saves the specified value to be applied.
 func (s *SensorArg) SetName(name string) {
 	if s.args == nil {
@@ -43,6 +51,22 @@ func (s *SensorConfigArg) Errors() map[string]ResponseError {
 	return s.errors
 }
 
+// Validate checks the values which have been set, returning ValidationErrors for any which the bridge would reject.
+func (s *SensorConfigArg) Validate() error {
+	v := newValidator(s.args)
+	v.intRange("battery", 0, 100)
+	v.oneOf("alert", "none", "select", "lselect")
+	v.length("url", 0, 64)
+	v.matches("lat", latitudePattern, "DDD.DDDD{N|S}")
+	v.matches("long", longitudePattern, "DDD.DDDD{E|W}")
+	v.intRange("sunriseoffset", -120, 120)
+	v.intRange("sunsetoffset", -120, 120)
+	v.intRange("tholddark", 0, 65534)
+	v.intRange("tholdoffset", 1, 65534)
+
+	return v.err()
+}
+
 // SetIsOn sets the sensor to be on.
 func (s *SensorConfigArg) SetIsOn(isOn bool) {
 	if s.args == nil {
@@ -243,6 +267,14 @@ func (s *SensorStateArg) Errors() map[string]ResponseError {
 	return s.errors
 }
 
+// Validate checks the values which have been set, returning ValidationErrors for any which the bridge would reject.
+func (s *SensorStateArg) Validate() error {
+	v := newValidator(s.args)
+	v.intRange("humidity", 0, 10000)
+
+	return v.err()
+}
+
 // SetIsOpen sets the specified option to be applied.
 func (s *SensorStateArg) SetIsOpen(isOpen bool) {
 	if s.args == nil {
@@ -0,0 +1,79 @@
+package hue
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBridge_PairSensors(t *testing.T) {
+	defer func(interval time.Duration) { sensorScanPollInterval = interval }(sensorScanPollInterval)
+	sensorScanPollInterval = 10 * time.Millisecond
+
+	var polls int64
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			w.Write([]byte(`[{"success":{"/sensors":"Searching for new devices"}}]`))
+		case r.URL.Path == "/api/testuser/sensors/new":
+			if atomic.AddInt64(&polls, 1) < 3 {
+				w.Write([]byte(`{"lastscan":"active"}`))
+				return
+			}
+			w.Write([]byte(`{"7":{"name":"Hue motion sensor 1"},"lastscan":"2026-10-17T12:00:00"}`))
+		case r.URL.Path == "/api/testuser/sensors/7":
+			w.Write([]byte(`{"name":"Hue motion sensor 1","type":"ZLLPresence","modelid":"SML001","state":{"presence":false}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	sensors, err := bridge.PairSensors(context.Background())
+	if err != nil {
+		t.Fatalf("Unable to pair sensors: %s\n", err.Error())
+	}
+
+	if len(sensors) != 1 || sensors[0].ID != "7" || sensors[0].ModelID != "SML001" {
+		t.Errorf("Expected the new sensor to be returned, got %+v\n", sensors)
+	}
+	if n := atomic.LoadInt64(&polls); n != 3 {
+		t.Errorf("Expected the search to be polled until it completed, got %d polls\n", n)
+	}
+}
+
+func TestBridge_PairSensorsCancelled(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.Write([]byte(`[{"success":{"/sensors":"Searching for new devices"}}]`))
+			return
+		}
+		w.Write([]byte(`{"lastscan":"active"}`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := bridge.PairSensors(ctx); err != context.DeadlineExceeded {
+		t.Errorf("Expected the deadline to be exceeded, got %v\n", err)
+	}
+}
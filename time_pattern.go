@@ -0,0 +1,268 @@
+package hue
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTimePattern is returned if a time pattern string is not in one of the formats supported by the bridge.
+var ErrInvalidTimePattern = errors.New("invalid time pattern")
+
+const (
+	// TimePatternAbsolute fires once at a specific date and time.
+	TimePatternAbsolute = iota
+	// TimePatternRecurring fires at a specific time on each of the selected weekdays.
+	TimePatternRecurring
+	// TimePatternTimer fires once after the specified duration has elapsed.
+	TimePatternTimer
+	// TimePatternRecurringTimer fires each time the specified duration has elapsed.
+	TimePatternRecurringTimer
+)
+
+const absoluteTimeLayout = "2006-01-02T15:04:05"
+
+// Weekdays is a bitmask of the days a recurring time pattern fires on.
+type Weekdays uint8
+
+const (
+	// WeekdaySunday selects Sunday.
+	WeekdaySunday Weekdays = 1 << iota
+	// WeekdaySaturday selects Saturday.
+	WeekdaySaturday
+	// WeekdayFriday selects Friday.
+	WeekdayFriday
+	// WeekdayThursday selects Thursday.
+	WeekdayThursday
+	// WeekdayWednesday selects Wednesday.
+	WeekdayWednesday
+	// WeekdayTuesday selects Tuesday.
+	WeekdayTuesday
+	// WeekdayMonday selects Monday.
+	WeekdayMonday
+
+	// WeekdaysWorkweek selects Monday through Friday.
+	WeekdaysWorkweek = WeekdayMonday | WeekdayTuesday | WeekdayWednesday | WeekdayThursday | WeekdayFriday
+	// WeekdaysWeekend selects Saturday and Sunday.
+	WeekdaysWeekend = WeekdaySaturday | WeekdaySunday
+	// WeekdaysAll selects every day of the week.
+	WeekdaysAll = WeekdaysWorkweek | WeekdaysWeekend
+)
+
+// TimePattern represents one of the time formats used by the bridge for schedules.
+// The zero value of each field which isn't relevant to the Kind is ignored.
+type TimePattern struct {
+	Kind int
+
+	// Time is the date and time an absolute pattern fires at.
+	// Only the wall clock is used; the bridge interprets it in its configured timezone.
+	Time time.Time
+
+	// Weekdays are the days a recurring pattern fires on.
+	Weekdays Weekdays
+
+	// Duration is the time of day for a recurring pattern, or the length of a timer.
+	Duration time.Duration
+
+	// Repeat is the number of times a recurring timer fires; 0 repeats forever.
+	Repeat int
+
+	// Random is the maximum random delay added each time the pattern fires.
+	Random time.Duration
+
+	// Raw is the string reported by the bridge if it couldn't be parsed, in which case the other fields are unset.
+	// It is sent back to the bridge unchanged.
+	Raw string
+}
+
+// NewAbsoluteTime creates a pattern which fires once at the specified time.
+func NewAbsoluteTime(t time.Time) TimePattern {
+	return TimePattern{Kind: TimePatternAbsolute, Time: t}
+}
+
+// NewRecurringTime creates a pattern which fires at the specified time of day on each of the specified days.
+func NewRecurringTime(days Weekdays, timeOfDay time.Duration) TimePattern {
+	return TimePattern{Kind: TimePatternRecurring, Weekdays: days, Duration: timeOfDay}
+}
+
+// NewTimer creates a pattern which fires once the specified duration has elapsed.
+func NewTimer(d time.Duration) TimePattern {
+	return TimePattern{Kind: TimePatternTimer, Duration: d}
+}
+
+// NewRecurringTimer creates a pattern which fires every time the specified duration elapses, up to the specified number of times.
+// A repeat of 0 fires forever.
+func NewRecurringTimer(repeat int, d time.Duration) TimePattern {
+	return TimePattern{Kind: TimePatternRecurringTimer, Repeat: repeat, Duration: d}
+}
+
+// ParseTimePattern converts the string representation used by the bridge into a time pattern.
+func ParseTimePattern(s string) (TimePattern, error) {
+	var tp TimePattern
+
+	if idx := strings.Index(s, "A"); idx >= 0 {
+		random, err := parseClock(s[idx+1:])
+		if err != nil {
+			return tp, err
+		}
+
+		tp.Random = random
+		s = s[:idx]
+	}
+
+	var err error
+
+	switch {
+	case strings.HasPrefix(s, "W"):
+		parts := strings.SplitN(s[1:], "/", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[1], "T") {
+			return tp, ErrInvalidTimePattern
+		}
+
+		days, err := strconv.ParseUint(parts[0], 10, 8)
+		if err != nil || days > uint64(WeekdaysAll) {
+			return tp, ErrInvalidTimePattern
+		}
+
+		tp.Kind = TimePatternRecurring
+		tp.Weekdays = Weekdays(days)
+		tp.Duration, err = parseClock(parts[1][1:])
+		if err != nil {
+			return tp, err
+		}
+
+	case strings.HasPrefix(s, "R"):
+		parts := strings.SplitN(s[1:], "/", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[1], "PT") {
+			return tp, ErrInvalidTimePattern
+		}
+
+		if len(parts[0]) > 0 {
+			tp.Repeat, err = strconv.Atoi(parts[0])
+			if err != nil || tp.Repeat < 1 || tp.Repeat > 99 {
+				return tp, ErrInvalidTimePattern
+			}
+		}
+
+		tp.Kind = TimePatternRecurringTimer
+		tp.Duration, err = parseClock(parts[1][2:])
+		if err != nil {
+			return tp, err
+		}
+
+	case strings.HasPrefix(s, "PT"):
+		tp.Kind = TimePatternTimer
+		tp.Duration, err = parseClock(s[2:])
+		if err != nil {
+			return tp, err
+		}
+
+	default:
+		tp.Kind = TimePatternAbsolute
+		tp.Time, err = time.Parse(absoluteTimeLayout, s)
+		if err != nil {
+			return tp, ErrInvalidTimePattern
+		}
+	}
+
+	return tp, nil
+}
+
+// String returns the representation of the pattern used by the bridge.
+func (tp TimePattern) String() string {
+	if len(tp.Raw) > 0 {
+		return tp.Raw
+	}
+
+	var s string
+
+	switch tp.Kind {
+	case TimePatternAbsolute:
+		s = tp.Time.Format(absoluteTimeLayout)
+	case TimePatternRecurring:
+		s = fmt.Sprintf("W%d/T%s", tp.Weekdays&WeekdaysAll, formatClock(tp.Duration))
+	case TimePatternTimer:
+		s = "PT" + formatClock(tp.Duration)
+	case TimePatternRecurringTimer:
+		s = "R"
+		if tp.Repeat > 0 {
+			s += fmt.Sprintf("%02d", tp.Repeat)
+		}
+		s += "/PT" + formatClock(tp.Duration)
+	}
+
+	if tp.Random > 0 {
+		s += "A" + formatClock(tp.Random)
+	}
+
+	return s
+}
+
+// MarshalJSON encodes the pattern as the string used by the bridge.
+func (tp TimePattern) MarshalJSON() ([]byte, error) {
+	return json.Marshal(tp.String())
+}
+
+// UnmarshalJSON decodes the pattern from the string used by the bridge.
+// A string which can't be parsed is kept in Raw, so a single unexpected pattern doesn't prevent the rest of a response
+// from being decoded.
+func (tp *TimePattern) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	if len(s) < 1 {
+		*tp = TimePattern{}
+		return nil
+	}
+
+	ret, err := ParseTimePattern(s)
+	if err == ErrInvalidTimePattern {
+		*tp = TimePattern{Raw: s}
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	*tp = ret
+	return nil
+}
+
+// parseClock converts a hh:mm:ss string into a duration.
+func parseClock(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, ErrInvalidTimePattern
+	}
+
+	var values [3]int
+	for i, part := range parts {
+		if len(part) != 2 {
+			return 0, ErrInvalidTimePattern
+		}
+
+		v, err := strconv.Atoi(part)
+		if err != nil || v < 0 {
+			return 0, ErrInvalidTimePattern
+		}
+
+		values[i] = v
+	}
+
+	if values[0] > 23 || values[1] > 59 || values[2] > 59 {
+		return 0, ErrInvalidTimePattern
+	}
+
+	return time.Duration(values[0])*time.Hour + time.Duration(values[1])*time.Minute + time.Duration(values[2])*time.Second, nil
+}
+
+// formatClock converts a duration into a hh:mm:ss string.
+func formatClock(d time.Duration) string {
+	secs := int(d / time.Second)
+
+	return fmt.Sprintf("%02d:%02d:%02d", secs/3600, (secs/60)%60, secs%60)
+}
@@ -0,0 +1,100 @@
+package hue
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+var timePatternTests = []struct {
+	str string
+	tp  TimePattern
+}{
+	{
+		str: "2014-09-20T19:35:26",
+		tp:  NewAbsoluteTime(time.Date(2014, 9, 20, 19, 35, 26, 0, time.UTC)),
+	},
+	{
+		str: "2014-09-20T19:35:26A00:30:00",
+		tp:  TimePattern{Kind: TimePatternAbsolute, Time: time.Date(2014, 9, 20, 19, 35, 26, 0, time.UTC), Random: 30 * time.Minute},
+	},
+	{
+		str: "W127/T07:00:00",
+		tp:  NewRecurringTime(WeekdaysAll, 7*time.Hour),
+	},
+	{
+		str: "W3/T10:15:30A00:00:45",
+		tp:  TimePattern{Kind: TimePatternRecurring, Weekdays: WeekdaysWeekend, Duration: 10*time.Hour + 15*time.Minute + 30*time.Second, Random: 45 * time.Second},
+	},
+	{
+		str: "PT00:10:00",
+		tp:  NewTimer(10 * time.Minute),
+	},
+	{
+		str: "R/PT00:00:30",
+		tp:  NewRecurringTimer(0, 30*time.Second),
+	},
+	{
+		str: "R05/PT01:00:00A00:05:00",
+		tp:  TimePattern{Kind: TimePatternRecurringTimer, Repeat: 5, Duration: time.Hour, Random: 5 * time.Minute},
+	},
+}
+
+func TestParseTimePattern(t *testing.T) {
+	for _, test := range timePatternTests {
+		tp, err := ParseTimePattern(test.str)
+		if err != nil {
+			t.Errorf("Unable to parse %s: %s\n", test.str, err.Error())
+			continue
+		}
+
+		if tp.Kind != test.tp.Kind || !tp.Time.Equal(test.tp.Time) || tp.Weekdays != test.tp.Weekdays || tp.Duration != test.tp.Duration || tp.Repeat != test.tp.Repeat || tp.Random != test.tp.Random {
+			t.Errorf("Incorrect parse of %s, expected %+v, got %+v\n", test.str, test.tp, tp)
+		}
+	}
+}
+
+func TestTimePattern_String(t *testing.T) {
+	for _, test := range timePatternTests {
+		if s := test.tp.String(); s != test.str {
+			t.Errorf("Incorrect formatting of %+v, expected %s, got %s\n", test.tp, test.str, s)
+		}
+	}
+}
+
+func TestParseTimePattern_Invalid(t *testing.T) {
+	invalid := []string{
+		"",
+		"W128/T07:00:00",
+		"W127/07:00:00",
+		"PT24:00:00",
+		"PT00:60:00",
+		"R100/PT00:00:30",
+		"2014-09-20 19:35:26",
+		"PT00:10:00A1",
+	}
+
+	for _, str := range invalid {
+		if _, err := ParseTimePattern(str); err != ErrInvalidTimePattern {
+			t.Errorf("Expected %s to be invalid, got %v\n", str, err)
+		}
+	}
+}
+
+func TestTimePattern_UnmarshalInvalid(t *testing.T) {
+	var tp TimePattern
+	if err := json.Unmarshal([]byte(`"sunset+00:30"`), &tp); err != nil {
+		t.Fatalf("Unable to unmarshal pattern: %s\n", err.Error())
+	}
+	if tp.Raw != "sunset+00:30" {
+		t.Errorf("Expected the pattern to be kept, got %+v\n", tp)
+	}
+
+	data, err := json.Marshal(tp)
+	if err != nil {
+		t.Fatalf("Unable to marshal pattern: %s\n", err.Error())
+	}
+	if string(data) != `"sunset+00:30"` {
+		t.Errorf("Expected the pattern to be sent unchanged, got %s\n", data)
+	}
+}
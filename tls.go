@@ -0,0 +1,163 @@
+package hue
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrCertificateMismatch is returned if the certificate presented by the bridge does not match its ID or pinned fingerprint.
+	ErrCertificateMismatch = errors.New("bridge certificate does not match bridge ID or pinned fingerprint")
+	// ErrNoCertificate is returned if the bridge did not present a certificate.
+	ErrNoCertificate = errors.New("bridge did not present a certificate")
+	// ErrUnverifiableCertificate is returned if a certificate is presented before the bridge ID is known and no fingerprint is pinned.
+	ErrUnverifiableCertificate = errors.New("bridge certificate cannot be verified without the bridge ID or a pinned fingerprint")
+	// ErrUnsupportedTransport is returned by InitURL if WithTLS was used along with a transport which can't be configured for TLS.
+	ErrUnsupportedTransport = errors.New("transport must be an *http.Transport or a RecordingTransport to use TLS")
+)
+
+// WithTLS makes all API requests to the bridge over HTTPS.
+// The bridge presents a self-signed certificate, so rather than verifying it against a certificate authority it must either
+// match the CertificateFingerprint of the bridge, if set, or have a common name matching the ID of the bridge.
+// The transport of any client supplied with WithHTTPClient or WithTransport is reused if it is an *http.Transport, or a
+// RecordingTransport wrapping one; any other transport can't be configured, and InitURL returns ErrUnsupportedTransport.
+// The description is retrieved before the bridge ID is known, so it must be retrieved over HTTP, as bridges serve it,
+// unless CertificateFingerprint is set.
+func WithTLS() BridgeOption {
+	return func(b *Bridge) {
+		b.useTLS = true
+	}
+}
+
+// Fingerprint returns the value used to pin the specified certificate, which is the hex encoded SHA-256 hash of the certificate.
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// wrappingTransport is implemented by transports, such as RecordingTransport, which perform requests using another transport.
+type wrappingTransport interface {
+	// innerTransport returns the transport requests are performed with; nil means http.DefaultTransport.
+	innerTransport() http.RoundTripper
+	// withTransport returns a transport which behaves the same, but performs requests with the supplied transport.
+	withTransport(transport http.RoundTripper) http.RoundTripper
+}
+
+// enableTLS replaces the transport of the bridge with one which verifies the bridge certificate.
+func (b *Bridge) enableTLS() {
+	client := *b.httpClient()
+
+	transport, err := b.tlsRoundTripper(client.Transport)
+	if err != nil {
+		b.tlsErr = err
+		return
+	}
+
+	client.Transport = transport
+	b.client = &client
+}
+
+// tlsRoundTripper returns a transport which verifies the bridge certificate in place of the supplied one.
+// The supplied transport is never changed, as it may be shared with other bridges.
+func (b *Bridge) tlsRoundTripper(transport http.RoundTripper) (http.RoundTripper, error) {
+	switch transport := transport.(type) {
+	case nil:
+		return b.tlsTransport(http.DefaultTransport.(*http.Transport)), nil
+	case *http.Transport:
+		return b.tlsTransport(transport), nil
+	case wrappingTransport:
+		inner, err := b.tlsRoundTripper(transport.innerTransport())
+		if err != nil {
+			return nil, err
+		}
+
+		// The wrapper is kept so a recorder supplied by the caller still records what this bridge sends.
+		return transport.withTransport(inner), nil
+	}
+
+	return nil, ErrUnsupportedTransport
+}
+
+// tlsTransport returns a copy of the supplied transport which verifies the bridge certificate.
+func (b *Bridge) tlsTransport(transport *http.Transport) *http.Transport {
+	transport = transport.Clone()
+	transport.TLSClientConfig = &tls.Config{
+		// The chain can't be verified as the certificate is self-signed; verifyConnection checks the certificate instead.
+		InsecureSkipVerify: true,
+		VerifyConnection:   b.verifyConnection,
+	}
+
+	return transport
+}
+
+// verifyConnection checks the certificate presented by the bridge against the pinned fingerprint, or the ID of the bridge.
+// If no fingerprint has been pinned, the fingerprint of a certificate matching the bridge ID is saved to CertificateFingerprint.
+func (b *Bridge) verifyConnection(cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) < 1 {
+		return ErrNoCertificate
+	}
+
+	leaf := cs.PeerCertificates[0]
+	fingerprint := Fingerprint(leaf)
+
+	b.shared().mu.Lock()
+	defer b.shared().mu.Unlock()
+
+	if len(b.CertificateFingerprint) > 0 {
+		pinned := strings.ToLower(strings.Replace(b.CertificateFingerprint, ":", "", -1))
+		if pinned != fingerprint {
+			return ErrCertificateMismatch
+		}
+
+		return nil
+	}
+
+	// Without the ID there is nothing to check the certificate against, so it could have been presented by anyone.
+	if len(b.id) < 1 {
+		return ErrUnverifiableCertificate
+	}
+
+	if !certificateNameMatchesID(leaf.Subject.CommonName, b.id) {
+		return ErrCertificateMismatch
+	}
+
+	b.CertificateFingerprint = fingerprint
+	return nil
+}
+
+// certificateNameMatchesID returns whether the common name of a bridge certificate identifies the bridge with the specified ID.
+// The ID may either be the full bridge ID, or the serial number from the description, which is the MAC address of the bridge.
+func certificateNameMatchesID(commonName string, id string) bool {
+	commonName = strings.ToLower(commonName)
+	id = strings.ToLower(id)
+
+	if commonName == id {
+		return true
+	}
+
+	// The bridge ID is derived from the MAC address by inserting fffe in the middle of it.
+	if len(id) == 12 {
+		return commonName == id[:6]+"fffe"+id[6:]
+	}
+
+	return false
+}
+
+// tlsBaseURL converts the base URL reported by the bridge description into the equivalent HTTPS URL.
+func tlsBaseURL(baseURL *url.URL) {
+	if baseURL.Scheme != "http" {
+		return
+	}
+
+	baseURL.Scheme = "https"
+
+	if baseURL.Port() == "80" {
+		baseURL.Host = baseURL.Hostname()
+	}
+}
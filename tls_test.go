@@ -0,0 +1,203 @@
+package hue
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// newTLSTestServer starts a bridge test server using a self-signed certificate with the specified common name.
+func newTLSTestServer(t *testing.T, commonName string) (*httptest.Server, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Unable to generate key: %s\n", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unable to create certificate: %s\n", err.Error())
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("Unable to parse certificate: %s\n", err.Error())
+	}
+
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+
+	srv := newUnstartedTestServer(api, &conns)
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+	srv.StartTLS()
+
+	return srv, cert
+}
+
+// newDescriptionServer starts a server which serves the description of the supplied bridge test server over HTTP, as a
+// bridge does, returning the URL of the description.
+func newDescriptionServer(t *testing.T, srv *httptest.Server) *url.URL {
+	desc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		fmt.Fprintf(w, testDescription, srv.URL)
+	}))
+	t.Cleanup(desc.Close)
+
+	return mustParseURL(t, desc.URL+"/description.xml")
+}
+
+func TestBridge_TLSMatchingID(t *testing.T) {
+	srv, cert := newTLSTestServer(t, "001788fffe100491")
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithTLS())
+	if err := bridge.InitURL(newDescriptionServer(t, srv)); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if _, err := bridge.Lights(); err != nil {
+		t.Fatalf("Unable to retrieve lights: %s\n", err.Error())
+	}
+
+	if bridge.CertificateFingerprint != Fingerprint(cert) {
+		t.Errorf("Expected fingerprint %s to be pinned, got %s\n", Fingerprint(cert), bridge.CertificateFingerprint)
+	}
+}
+
+func TestBridge_TLSMismatchedID(t *testing.T) {
+	srv, _ := newTLSTestServer(t, "001788fffe999999")
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithTLS())
+	if err := bridge.InitURL(newDescriptionServer(t, srv)); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if _, err := bridge.Lights(); !errors.Is(err, ErrCertificateMismatch) {
+		t.Errorf("Expected certificate mismatch, got %v\n", err)
+	}
+}
+
+func TestBridge_TLSPinnedFingerprint(t *testing.T) {
+	srv, _ := newTLSTestServer(t, "001788fffe100491")
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithTLS())
+	bridge.CertificateFingerprint = "00:11:22"
+
+	if err := bridge.InitURL(mustParseURL(t, srv.URL+"/description.xml")); !errors.Is(err, ErrCertificateMismatch) {
+		t.Errorf("Expected certificate mismatch, got %v\n", err)
+	}
+}
+
+func TestBridge_TLSUnknownID(t *testing.T) {
+	srv, _ := newTLSTestServer(t, "001788fffe100491")
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithTLS())
+
+	if err := bridge.InitURL(mustParseURL(t, srv.URL+"/description.xml")); !errors.Is(err, ErrUnverifiableCertificate) {
+		t.Errorf("Expected an unverifiable certificate, got %v\n", err)
+	}
+}
+
+func TestBridge_TLSRecordingTransport(t *testing.T) {
+	srv, _ := newTLSTestServer(t, "001788fffe100491")
+	defer srv.Close()
+
+	recorder := &RecordingTransport{}
+
+	bridge := NewBridge("testuser", WithTransport(recorder), WithTLS())
+	if err := bridge.InitURL(newDescriptionServer(t, srv)); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	if _, err := bridge.Lights(); err != nil {
+		t.Fatalf("Unable to retrieve lights: %s\n", err.Error())
+	}
+	if n := len(recorder.Interactions()); n != 2 {
+		t.Errorf("Expected 2 interactions to be recorded, got %d\n", n)
+	}
+}
+
+func TestBridge_TLSSharedRecordingTransport(t *testing.T) {
+	srv, _ := newTLSTestServer(t, "001788fffe100491")
+	defer srv.Close()
+
+	recorder := &RecordingTransport{}
+
+	first := NewBridge("testuser", WithTransport(recorder), WithTLS())
+	if err := first.InitURL(newDescriptionServer(t, srv)); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	// The second bridge is pinned to a different certificate, which must not be used to verify the first.
+	second := NewBridge("testuser", WithTransport(recorder), WithTLS())
+	second.CertificateFingerprint = "00:11:22"
+
+	if recorder.Transport != nil {
+		t.Errorf("Expected the recorder not to be changed, got %T\n", recorder.Transport)
+	}
+
+	if _, err := first.Lights(); err != nil {
+		t.Fatalf("Unable to retrieve lights: %s\n", err.Error())
+	}
+	if err := second.InitURL(mustParseURL(t, srv.URL+"/description.xml")); !errors.Is(err, ErrCertificateMismatch) {
+		t.Errorf("Expected certificate mismatch, got %v\n", err)
+	}
+	if n := len(recorder.Interactions()); n != 2 {
+		t.Errorf("Expected 2 interactions to be recorded, got %d\n", n)
+	}
+}
+
+func TestBridge_TLSUnsupportedTransport(t *testing.T) {
+	bridge := NewBridge("testuser", WithTransport(&countingTransport{}), WithTLS())
+
+	if err := bridge.InitIP("127.0.0.1"); !errors.Is(err, ErrUnsupportedTransport) {
+		t.Errorf("Expected an unsupported transport, got %v\n", err)
+	}
+}
+
+func TestCertificateNameMatchesID(t *testing.T) {
+	if !certificateNameMatchesID("001788fffe100491", "001788100491") {
+		t.Errorf("Expected bridge ID to match MAC address\n")
+	}
+	if !certificateNameMatchesID("001788FFFE100491", "001788fffe100491") {
+		t.Errorf("Expected bridge ID to match regardless of case\n")
+	}
+	if certificateNameMatchesID("001788fffe100492", "001788100491") {
+		t.Errorf("Expected different bridge ID not to match\n")
+	}
+}
+
+func mustParseURL(t *testing.T, rawURL string) *url.URL {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatalf("Unable to parse %s: %s\n", rawURL, err.Error())
+	}
+
+	return u
+}
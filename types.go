@@ -1,6 +1,11 @@
 package hue
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+)
 
 type arg struct {
 	args    map[string]interface{}
@@ -19,22 +24,168 @@ var responseErrorTypes = map[int]string{
 	8:   "Parameter not modifiable",
 	11:  "Too many items in list",
 	12:  "Portal connection required",
+	101: "Link button not pressed",
+	201: "Parameter not modifiable, as the device is off",
+	301: "Group could not be created, group table full",
+	302: "Device could not be added to group, device limit reached",
 	901: "Internal error",
 }
 
+var (
+	// ErrUnauthorizedUser is reported if the username is not known to the bridge.
+	ErrUnauthorizedUser = newResponseErrorType(1)
+	// ErrInvalidJSON is reported if the request body could not be parsed.
+	ErrInvalidJSON = newResponseErrorType(2)
+	// ErrResourceNotAvailable is reported if the resource does not exist.
+	ErrResourceNotAvailable = newResponseErrorType(3)
+	// ErrMethodNotAvailable is reported if the resource does not support the request method.
+	ErrMethodNotAvailable = newResponseErrorType(4)
+	// ErrMissingParameter is reported if a required parameter was not supplied.
+	ErrMissingParameter = newResponseErrorType(5)
+	// ErrParameterNotAvailable is reported if the resource does not have the supplied parameter.
+	ErrParameterNotAvailable = newResponseErrorType(6)
+	// ErrInvalidValue is reported if the value of a parameter is out of range or of the wrong type.
+	ErrInvalidValue = newResponseErrorType(7)
+	// ErrParameterNotModifiable is reported if the parameter is read-only.
+	ErrParameterNotModifiable = newResponseErrorType(8)
+	// ErrTooManyItems is reported if a list contains more items than the bridge allows.
+	ErrTooManyItems = newResponseErrorType(11)
+	// ErrPortalConnectionRequired is reported if the bridge must be connected to the internet portal.
+	ErrPortalConnectionRequired = newResponseErrorType(12)
+	// ErrLinkButtonNotPressed is reported if pairing was attempted before the link button was pressed.
+	ErrLinkButtonNotPressed = newResponseErrorType(101)
+	// ErrDeviceOff is reported if a light state was changed while the light is off.
+	ErrDeviceOff = newResponseErrorType(201)
+	// ErrGroupTableFull is reported if no more groups can be created.
+	ErrGroupTableFull = newResponseErrorType(301)
+	// ErrGroupDeviceLimitReached is reported if no more lights can be added to a group.
+	ErrGroupDeviceLimitReached = newResponseErrorType(302)
+	// ErrInternal is reported if the bridge failed to process the request.
+	ErrInternal = newResponseErrorType(901)
+)
+
+// newResponseErrorType creates the sentinel value matching every response error of the specified type.
+func newResponseErrorType(errorType int) ResponseError {
+	return ResponseError{
+		Type:        errorType,
+		Description: responseErrorTypes[errorType],
+	}
+}
+
 // ResponseError is the error message returned if the given entry is invalid.
 // The address refers to the component which failed to change; the Type maps to errorTypes above.
+// Errors of a particular type can be detected using errors.Is with the corresponding sentinel, such as ErrUnauthorizedUser.
 type ResponseError struct {
 	Type        int    `json:"type"`
 	Description string `json:"description"`
 	Address     string `json:"address"`
 }
 
+// Error returns the description of the error reported by the bridge.
+func (e ResponseError) Error() string {
+	if len(e.Address) < 1 {
+		return e.Description
+	}
+
+	return e.Description + " (" + e.Address + ")"
+}
+
+// Is reports whether the target is a response error of the same type.
+func (e ResponseError) Is(target error) bool {
+	t, ok := target.(ResponseError)
+	return ok && t.Type == e.Type
+}
+
+// ResponseErrors is returned if the bridge rejected one or more of the values supplied to a Set call.
+// It is keyed by the name of each value which failed to change, matching the Errors of the supplied Arg.
+type ResponseErrors map[string]ResponseError
+
+// Error returns the descriptions of each of the errors reported by the bridge.
+func (e ResponseErrors) Error() string {
+	keys := make([]string, 0, len(e))
+	for key := range e {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	msgs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		msgs = append(msgs, key+": "+e[key].Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns each of the errors reported by the bridge, allowing them to be inspected with errors.Is and errors.As.
+func (e ResponseErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// newResponseErrors returns the supplied per-key errors as a single error, or nil if there are none.
+func newResponseErrors(errs map[string]ResponseError) error {
+	if len(errs) < 1 {
+		return nil
+	}
+
+	ret := make(ResponseErrors, len(errs))
+	for key, err := range errs {
+		ret[key] = err
+	}
+
+	return ret
+}
+
 // One of the entries in the response array returned by the API to a PUT/POST request.
 type responseEntry struct {
 	Success map[string]*json.RawMessage `json:"success"`
 	Error   ResponseError               `json:"error"`
 }
 
+// One of the entries in the response array returned by the API to a DELETE request.
+// The success value is a message, rather than a set of changed values.
+type deleteResponseEntry struct {
+	Success string        `json:"success"`
+	Error   ResponseError `json:"error"`
+}
+
 // The overall response array returned by the API to a PUT/POST request.
 type responseEntries []json.RawMessage
+
+// timestampLayout is the format of the timestamps reported by the bridge, which are in UTC.
+const timestampLayout = "2006-01-02T15:04:05"
+
+// ScanStatus represents the state of the most recent search for new devices.
+type ScanStatus struct {
+	// Active is set while the bridge is still searching for new devices.
+	Active bool
+
+	// LastScan is the time the most recent search completed; it is zero if no search has been run since the bridge started.
+	LastScan time.Time
+}
+
+// IsComplete returns whether a search has been run and is no longer active.
+func (s ScanStatus) IsComplete() bool {
+	return !s.Active && !s.LastScan.IsZero()
+}
+
+// parseScanStatus converts the lastscan value reported by the bridge into a scan status.
+func parseScanStatus(lastScan string) (ScanStatus, error) {
+	switch lastScan {
+	case "none":
+		return ScanStatus{}, nil
+	case "active":
+		return ScanStatus{Active: true}, nil
+	}
+
+	t, err := time.Parse(timestampLayout, lastScan)
+	if err != nil {
+		return ScanStatus{}, err
+	}
+
+	return ScanStatus{LastScan: t}, nil
+}
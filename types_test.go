@@ -0,0 +1,52 @@
+package hue
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBridge_SetLightStateErrors(t *testing.T) {
+	var conns int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[
+			{"success":{"/lights/1/state/on":true}},
+			{"error":{"type":201,"address":"/lights/1/state/bri","description":"parameter, bri, is not modifiable. Device is set to off."}},
+			{"error":{"type":7,"address":"/lights/1/state/ct","description":"invalid value, 900, for parameter, ct"}}
+		]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser")
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	var args LightStateArg
+	args.SetIsOn(true)
+	args.SetBrightness(100)
+	args.SetColourTemperature(900)
+
+	err := bridge.SetLightState("1", &args)
+
+	var respErrs ResponseErrors
+	if !errors.As(err, &respErrs) {
+		t.Fatalf("Expected ResponseErrors, got %v\n", err)
+	}
+	if len(respErrs) != 2 || len(args.Errors()) != 2 {
+		t.Errorf("Expected 2 errors, got %d (%d in args)\n", len(respErrs), len(args.Errors()))
+	}
+	if !errors.Is(err, ErrDeviceOff) || !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Expected device off and invalid value errors, got %s\n", err.Error())
+	}
+	if errors.Is(err, ErrUnauthorizedUser) {
+		t.Errorf("Expected no unauthorized user error\n")
+	}
+
+	var respErr ResponseError
+	if !errors.As(err, &respErr) || len(respErr.Address) < 1 {
+		t.Errorf("Expected the individual errors to be accessible\n")
+	}
+}
@@ -1,8 +1,10 @@
 package hue
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 )
 
@@ -24,7 +26,10 @@ const (
 // Updater is an instance of a Hue bridge updater.
 type Updater struct {
 	bridge *Bridge
-	state  int32
+
+	// mu guards state, which is read by State while Run updates it.
+	mu    sync.Mutex
+	state int32
 
 	checkInProgress bool
 
@@ -42,66 +47,112 @@ func NewUpdater(b *Bridge) Updater {
 
 // State exposes the current state of the updater.
 func (u *Updater) State() int32 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
 	return u.state
 }
 
+func (u *Updater) setState(state int32) {
+	u.mu.Lock()
+	u.state = state
+	u.mu.Unlock()
+}
+
 // Run begins the process of monitoring a bridge for updates then applying them.
 func (u *Updater) Run(results chan string, quit chan interface{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		select {
+		case <-quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	u.RunWithContext(ctx, results)
+}
+
+// RunWithContext is like Run, but stops monitoring the bridge once the supplied context is done.
+// Any check or update in progress is abandoned when the context is done.
+func (u *Updater) RunWithContext(ctx context.Context, results chan string) {
 	ticker := time.NewTicker(60 * time.Minute)
+	defer ticker.Stop()
+
+	report := func(msg string) {
+		select {
+		case results <- msg:
+		case <-ctx.Done():
+		}
+	}
 
 	for {
 		select {
 		case <-ticker.C:
-			switch u.state {
+			switch u.State() {
 			case NoUpdateAvailable, DownloadingSystemUpdate:
-				newState := u.checkForUpdate()
+				newState := u.checkForUpdate(ctx)
 
-				if newState == u.state {
+				if newState == u.State() {
 					break
 				} else if newState == LastRequestFailed {
-					results <- "Error checking for updates: " + u.err.Error()
+					report("Error checking for updates: " + u.err.Error())
 					break
 				} else if newState == DownloadingSystemUpdate {
-					results <- u.msg
+					report(u.msg)
 				} else if newState == SystemUpdateAvailable {
-					results <- u.msg
+					report(u.msg)
 
-					newState = u.executeUpdate()
+					newState = u.executeUpdate(ctx)
 
 					if newState == LastRequestFailed {
-						results <- "Error applying update: " + u.err.Error()
-						u.state = SystemUpdateAvailable
+						report("Error applying update: " + u.err.Error())
+						u.setState(SystemUpdateAvailable)
 						break
 					} else {
-						results <- u.msg
+						report(u.msg)
 					}
 				}
 
-				u.state = newState
+				u.setState(newState)
 
 			case SystemUpdateAvailable:
-				newState := u.executeUpdate()
+				newState := u.executeUpdate(ctx)
 
 				if newState == LastRequestFailed {
-					results <- "Error applying update: " + u.err.Error()
+					report("Error applying update: " + u.err.Error())
 				} else {
-					results <- u.msg
-					u.state = newState
+					report(u.msg)
+					u.setState(newState)
 				}
 
 			case SystemUpdating:
 			default:
 			}
 
-		case <-quit:
-			ticker.Stop()
+		case <-ctx.Done():
 			return
 		}
 	}
 }
 
-func (u *Updater) checkForUpdate() int32 {
-	config, err := u.bridge.Config()
+// sleep waits for the specified duration, returning early with an error if the context is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (u *Updater) checkForUpdate(ctx context.Context) int32 {
+	config, err := u.bridge.ConfigWithContext(ctx)
 	if err != nil {
 		u.err = err
 		return LastRequestFailed
@@ -110,7 +161,7 @@ func (u *Updater) checkForUpdate() int32 {
 	}
 
 	if config.SwUpdate.State == 0 || config.SwUpdate.State == 1 {
-		err = u.bridge.CheckForUpdate()
+		err = u.bridge.CheckForUpdateWithContext(ctx)
 		if err != nil {
 			u.err = err
 			return LastRequestFailed
@@ -118,9 +169,12 @@ func (u *Updater) checkForUpdate() int32 {
 
 		itr := 0
 		for {
-			time.Sleep(time.Minute)
+			if err = sleep(ctx, time.Minute); err != nil {
+				u.err = err
+				return LastRequestFailed
+			}
 
-			checkConfig, err := u.bridge.Config()
+			checkConfig, err := u.bridge.ConfigWithContext(ctx)
 
 			if err != nil {
 				u.err = err
@@ -160,31 +214,32 @@ func (u *Updater) checkForUpdate() int32 {
 	}
 }
 
-func (u *Updater) executeUpdate() int32 {
-	startingConfig, err := u.bridge.Config()
+func (u *Updater) executeUpdate(ctx context.Context) int32 {
+	startingConfig, err := u.bridge.ConfigWithContext(ctx)
 	if err != nil {
 		u.err = err
 		return LastRequestFailed
 	}
 
-	u.bridge.updateInProgress = true
-	defer func() {
-		u.bridge.updateInProgress = false
-	}()
+	u.bridge.setUpdating(true)
+	defer u.bridge.setUpdating(false)
 
-	err = u.bridge.StartUpdate()
+	err = u.bridge.StartUpdateWithContext(ctx)
 	if err != nil {
 		u.err = err
 		return LastRequestFailed
 	}
 
-	u.state = SystemUpdating
+	u.setState(SystemUpdating)
 
 	itr := 0
 	for {
-		time.Sleep(time.Minute)
+		if err = sleep(ctx, time.Minute); err != nil {
+			u.err = err
+			return LastRequestFailed
+		}
 
-		checkConfig, err := u.bridge.Config()
+		checkConfig, err := u.bridge.ConfigWithContext(ctx)
 
 		// TODO: further error checking to differentiate between 'bridge is unavailable because rebooting' and 'bridge having true issues'
 		if err != nil && itr < 5 {
@@ -210,12 +265,9 @@ func (u *Updater) executeUpdate() int32 {
 		// Reset the notify flag then return.
 
 		// We don't care if it fails; we still consider the update to be complete.
-		_ = u.bridge.FinishUpdate()
+		_ = u.bridge.FinishUpdateWithContext(ctx)
 
 		u.msg = "Bridge updated to " + checkConfig.APIVersion
 		return checkConfig.SwUpdate.State
 	}
-
-	u.err = errors.New("unknown state detected")
-	return LastRequestFailed
 }
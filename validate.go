@@ -0,0 +1,266 @@
+package hue
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxNameLength is the longest name the bridge accepts for most resources.
+	maxNameLength = 32
+	// maxDescriptionLength is the longest description the bridge accepts.
+	maxDescriptionLength = 64
+)
+
+var (
+	// latitudePattern matches a latitude in the format used by the daylight sensor, such as 051.9000N.
+	latitudePattern = regexp.MustCompile(`^\d{3}\.\d{4}[NS]$`)
+	// longitudePattern matches a longitude in the format used by the daylight sensor, such as 005.9000E.
+	longitudePattern = regexp.MustCompile(`^\d{3}\.\d{4}[EW]$`)
+)
+
+// ValidationError describes a value which would be rejected by the bridge.
+type ValidationError struct {
+	Key    string
+	Value  interface{}
+	Reason string
+}
+
+func (e ValidationError) Error() string {
+	return "invalid value, " + fmt.Sprint(e.Value) + ", for parameter, " + e.Key + ": " + e.Reason
+}
+
+// Is reports the error as ErrInvalidValue, which is what the bridge returns for the same value.
+func (e ValidationError) Is(target error) bool {
+	t, ok := target.(ResponseError)
+	return ok && t.Type == ErrInvalidValue.Type
+}
+
+// ValidationErrors contains the problems found with an arg, keyed by the name of the value.
+type ValidationErrors map[string]ValidationError
+
+// Error returns the descriptions of each of the invalid values.
+func (e ValidationErrors) Error() string {
+	keys := make([]string, 0, len(e))
+	for key := range e {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	msgs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		msgs = append(msgs, e[key].Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns each of the invalid values, allowing them to be inspected with errors.Is and errors.As.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// WithValidation validates every arg before it is sent, returning the ValidationErrors without contacting the bridge.
+func WithValidation() BridgeOption {
+	return func(b *Bridge) {
+		b.validateArgs = true
+	}
+}
+
+// validatable is implemented by every arg.
+type validatable interface {
+	Validate() error
+}
+
+// validate checks the supplied arg if the bridge was created using WithValidation.
+func (b *Bridge) validate(args validatable) error {
+	if !b.validateArgs {
+		return nil
+	}
+
+	return args.Validate()
+}
+
+// validator collects the problems found with the values set in an arg.
+type validator struct {
+	values map[string]interface{}
+	errs   ValidationErrors
+}
+
+func newValidator(values map[string]interface{}) *validator {
+	return &validator{values: values}
+}
+
+// fail records that the value of the specified key is invalid.
+func (v *validator) fail(key string, reason string) {
+	if v.errs == nil {
+		v.errs = make(ValidationErrors)
+	}
+
+	v.errs[key] = ValidationError{
+		Key:    key,
+		Value:  v.values[key],
+		Reason: reason,
+	}
+}
+
+// intRange checks that the integer value of the specified key, if set, is between min and max inclusive.
+func (v *validator) intRange(key string, min int64, max int64) {
+	value, ok := v.values[key]
+	if !ok {
+		return
+	}
+
+	rv := reflect.ValueOf(value)
+
+	var n int64
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = int64(rv.Uint())
+	default:
+		return
+	}
+
+	if n < min || n > max {
+		v.fail(key, fmt.Sprintf("must be between %d and %d", min, max))
+	}
+}
+
+// xyRange checks that both coordinates of the XY value of the specified key, if set, are between 0 and 1.
+func (v *validator) xyRange(key string) {
+	if xy, ok := v.values[key].([2]float64); ok {
+		if xy[0] < 0 || xy[0] > 1 || xy[1] < 0 || xy[1] > 1 {
+			v.fail(key, "coordinates must be between 0 and 1")
+		}
+	}
+}
+
+// oneOf checks that the string value of the specified key, if set, is one of the allowed values.
+func (v *validator) oneOf(key string, allowed ...string) {
+	value, ok := v.values[key].(string)
+	if !ok {
+		return
+	}
+
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+
+	v.fail(key, "must be one of "+strings.Join(allowed, ", "))
+}
+
+// length checks that the string value of the specified key, if set, has between min and max characters.
+func (v *validator) length(key string, min int, max int) {
+	value, ok := v.values[key].(string)
+	if !ok {
+		return
+	}
+
+	if n := utf8.RuneCountInString(value); n < min || n > max {
+		v.fail(key, fmt.Sprintf("must be between %d and %d characters", min, max))
+	}
+}
+
+// count checks that the list value of the specified key, if set, has between min and max entries.
+func (v *validator) count(key string, min int, max int) {
+	value, ok := v.values[key]
+	if !ok {
+		return
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return
+	}
+
+	if n := rv.Len(); n < min || n > max {
+		v.fail(key, fmt.Sprintf("must have between %d and %d entries", min, max))
+	}
+}
+
+// matches checks that the string value of the specified key, if set, matches the pattern.
+func (v *validator) matches(key string, pattern *regexp.Regexp, format string) {
+	if value, ok := v.values[key].(string); ok && !pattern.MatchString(value) {
+		v.fail(key, "must be in the format "+format)
+	}
+}
+
+// ipv4 checks that the string value of the specified key, if set, is an IPv4 address.
+func (v *validator) ipv4(key string) {
+	if value, ok := v.values[key].(string); ok {
+		if ip := net.ParseIP(value); ip == nil || ip.To4() == nil {
+			v.fail(key, "must be an IPv4 address")
+		}
+	}
+}
+
+// commands checks the bodies of the schedule command, or the rule actions, of the specified key, if set.
+// Each body is checked by the arg for the resource its address refers to, with any problems keyed by the path to the value.
+func (v *validator) commands(key string) {
+	switch value := v.values[key].(type) {
+	case ScheduleCommand:
+		v.body(key+"/body", value.Address, value.Body)
+	case []RuleAction:
+		for idx, action := range value {
+			v.body(key+"/"+strconv.Itoa(idx)+"/body", action.Address, action.Body)
+		}
+	}
+}
+
+// body checks the body of a command sent to the specified address, recording any problems under the supplied key.
+// Bodies sent to resources without an arg, such as a scene, aren't checked.
+func (v *validator) body(key string, address string, body map[string]interface{}) {
+	var args validatable
+
+	parts := strings.Split(strings.Trim(address, "/"), "/")
+	if n := len(parts); n >= 3 {
+		switch parts[n-3] + "/" + parts[n-1] {
+		case "lights/state", "groups/action":
+			// A group action accepts the same values as a light state, along with a scene.
+			args = &LightStateArg{args: body}
+		case "sensors/state":
+			args = &SensorStateArg{args: body}
+		}
+	}
+
+	if args == nil {
+		return
+	}
+
+	errs, ok := args.Validate().(ValidationErrors)
+	if !ok {
+		return
+	}
+
+	if v.errs == nil {
+		v.errs = make(ValidationErrors)
+	}
+	for name, err := range errs {
+		err.Key = key + "/" + name
+		v.errs[err.Key] = err
+	}
+}
+
+// err returns the problems found, or nil if every value is valid.
+func (v *validator) err() error {
+	if len(v.errs) < 1 {
+		return nil
+	}
+
+	return v.errs
+}
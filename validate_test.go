@@ -0,0 +1,174 @@
+package hue
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestLightStateArg_Validate(t *testing.T) {
+	var args LightStateArg
+	args.SetIsOn(true)
+	args.SetBrightness(0)
+	args.SetColourTemperature(600)
+	args.SetXY(XY{X: 0.5, Y: 1.2})
+	args.SetAlert("flash")
+	args.SetEffect("none")
+
+	err := args.Validate()
+
+	var valErrs ValidationErrors
+	if !errors.As(err, &valErrs) {
+		t.Fatalf("Expected ValidationErrors, got %v\n", err)
+	}
+	for _, key := range []string{"bri", "ct", "xy", "alert"} {
+		if _, ok := valErrs[key]; !ok {
+			t.Errorf("Expected %s to be invalid, got %s\n", key, err.Error())
+		}
+	}
+	if len(valErrs) != 4 {
+		t.Errorf("Expected 4 invalid values, got %d\n", len(valErrs))
+	}
+	if !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Expected the errors to match ErrInvalidValue\n")
+	}
+
+	args.Reset()
+	args.SetBrightness(254)
+	args.SetColourTemperature(153)
+	if err = args.Validate(); err != nil {
+		t.Errorf("Expected valid values, got %s\n", err.Error())
+	}
+}
+
+func TestSensorConfigArg_Validate(t *testing.T) {
+	var args SensorConfigArg
+	args.SetBatteryLevel(101)
+	args.SetLatitude("51.9N")
+	args.SetLongitude("005.9000E")
+	args.SetSunriseOffset(-121)
+	args.SetDarkThresholdOffset(0)
+
+	var valErrs ValidationErrors
+	if err := args.Validate(); !errors.As(err, &valErrs) {
+		t.Fatalf("Expected ValidationErrors, got %v\n", err)
+	}
+	if len(valErrs) != 4 {
+		t.Errorf("Expected 4 invalid values, got %s\n", valErrs.Error())
+	}
+	if _, ok := valErrs["long"]; ok {
+		t.Errorf("Expected the longitude to be valid\n")
+	}
+}
+
+func TestConfigArg_Validate(t *testing.T) {
+	var args ConfigArg
+	args.SetName("Hue")
+	args.SetStaticAddress("192.168.1.300", "255.255.255.0", "192.168.1.1")
+
+	var valErrs ValidationErrors
+	if err := args.Validate(); !errors.As(err, &valErrs) {
+		t.Fatalf("Expected ValidationErrors, got %v\n", err)
+	}
+	if len(valErrs) != 2 || valErrs["name"].Key != "name" || valErrs["ipaddress"].Value != "192.168.1.300" {
+		t.Errorf("Expected the name and IP address to be invalid, got %s\n", valErrs.Error())
+	}
+}
+
+func TestBridge_WithValidation(t *testing.T) {
+	var conns int64
+	var requests int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		w.Write([]byte(`[{"error":{"type":7,"address":"/lights/1/state/ct","description":"invalid value, 900, for parameter, ct"}}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithValidation())
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+
+	var args LightStateArg
+	args.SetColourTemperature(900)
+
+	err := bridge.SetLightState("1", &args)
+
+	var valErrs ValidationErrors
+	if !errors.As(err, &valErrs) {
+		t.Fatalf("Expected ValidationErrors, got %v\n", err)
+	}
+	if n := atomic.LoadInt64(&requests); n != 0 {
+		t.Errorf("Expected the invalid state to not be sent, got %d requests\n", n)
+	}
+}
+
+func TestBridge_WithValidationCommandBodies(t *testing.T) {
+	var conns int64
+	var requests int64
+	api := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt64(&requests, 1)
+		w.Write([]byte(`[{"success":{"id":"1"}}]`))
+	})
+
+	srv := newTestServer(api, &conns)
+	defer srv.Close()
+
+	bridge := NewBridge("testuser", WithValidation())
+	if err := bridge.InitIP(srv.Listener.Addr().String()); err != nil {
+		t.Fatalf("Unable to initialize bridge: %s\n", err.Error())
+	}
+	atomic.StoreInt64(&requests, 0)
+
+	var state LightStateArg
+	state.SetColourTemperature(900)
+
+	var action GroupActionArg
+	action.SetBrightness(0)
+
+	schedule := Schedule{Name: "Test", Command: NewLightStateCommand("1", &state)}
+	err := bridge.CreateSchedule(&schedule)
+
+	var valErrs ValidationErrors
+	if !errors.As(err, &valErrs) {
+		t.Fatalf("Expected ValidationErrors, got %v\n", err)
+	}
+	if _, ok := valErrs["command/body/ct"]; !ok {
+		t.Errorf("Expected the colour temperature of the command to be invalid, got %v\n", valErrs)
+	}
+
+	var args ScheduleArg
+	args.SetCommand(NewGroupActionCommand("1", &action))
+	if err = bridge.SetSchedule("1", &args); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("Expected the brightness of the command to be invalid, got %v\n", err)
+	}
+
+	rule := Rule{
+		Name:       "Test",
+		Conditions: NewPresenceConditions("1", true),
+		Actions:    []RuleAction{NewSensorStateAction("2", &SensorStateArg{}), NewLightStateAction("1", &state)},
+	}
+	err = bridge.CreateRule(&rule)
+	if !errors.As(err, &valErrs) {
+		t.Fatalf("Expected ValidationErrors, got %v\n", err)
+	}
+	if _, ok := valErrs["actions/1/body/ct"]; !ok || len(valErrs) != 1 {
+		t.Errorf("Expected only the colour temperature of the second action to be invalid, got %v\n", valErrs)
+	}
+
+	if n := atomic.LoadInt64(&requests); n != 0 {
+		t.Errorf("Expected the invalid commands to not be sent, got %d requests\n", n)
+	}
+
+	// Valid commands are still sent.
+	state.SetColourTemperature(300)
+	if err = bridge.CreateSchedule(&schedule); err != nil {
+		t.Errorf("Unable to create schedule: %s\n", err.Error())
+	}
+	if n := atomic.LoadInt64(&requests); n != 1 {
+		t.Errorf("Expected the valid schedule to be sent, got %d requests\n", n)
+	}
+}